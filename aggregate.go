@@ -1,13 +1,18 @@
 package go_orm
 
+// Aggregate 聚合函数
+// 可以出现在 SELECT 里面，也可以作为 HAVING 的条件，例如 Count("Id").GT(5)
 type Aggregate struct {
-	arg   string
-	fn    string
-	alias string
+	arg      string
+	fn       string
+	alias    string
+	distinct bool
 }
 
 func (a Aggregate) selectable() {}
 
+func (a Aggregate) expr() {}
+
 func Avg(col string) Aggregate {
 	return Aggregate{
 		arg: col,
@@ -29,7 +34,68 @@ func Max(col string) Aggregate {
 	}
 }
 
+func Sum(col string) Aggregate {
+	return Aggregate{
+		arg: col,
+		fn:  "SUM",
+	}
+}
+
+// Count 传入 "*" 的时候对应 COUNT(*)
+func Count(col string) Aggregate {
+	return Aggregate{
+		arg: col,
+		fn:  "COUNT",
+	}
+}
+
+func CountDistinct(col string) Aggregate {
+	return Aggregate{
+		arg:      col,
+		fn:       "COUNT",
+		distinct: true,
+	}
+}
+
 func (a Aggregate) As(as string) Aggregate {
 	a.alias = as
 	return a
 }
+
+func (a Aggregate) Asc() OrderBy {
+	return OrderBy{
+		expr:  a,
+		order: "ASC",
+	}
+}
+
+func (a Aggregate) Desc() OrderBy {
+	return OrderBy{
+		expr:  a,
+		order: "DESC",
+	}
+}
+
+func (a Aggregate) EQ(val any) Predicate {
+	return newPredicate(a, opEQ, val)
+}
+
+func (a Aggregate) NEQ(val any) Predicate {
+	return newPredicate(a, opNEQ, val)
+}
+
+func (a Aggregate) GT(val any) Predicate {
+	return newPredicate(a, opGT, val)
+}
+
+func (a Aggregate) GTE(val any) Predicate {
+	return newPredicate(a, opGTE, val)
+}
+
+func (a Aggregate) LT(val any) Predicate {
+	return newPredicate(a, opLT, val)
+}
+
+func (a Aggregate) LTE(val any) Predicate {
+	return newPredicate(a, opLTE, val)
+}
@@ -1,19 +1,532 @@
 package go_orm
 
 import (
+	err2 "go-orm/internal/err"
 	"go-orm/internal/model"
 	"strings"
 )
 
 type builder struct {
-	m       *model.Model
-	sb      strings.Builder
-	args    []any
-	dialect Dialect
+	m    *model.Model
+	sb   strings.Builder
+	args []any
+	// core 提供方言，以及解析 Join 中其它表的元数据
+	core
+	// argOffset 作为子查询的时候，外层查询已有的参数个数
+	argOffset int
 }
 
 func (b *builder) quote(name string) {
-	b.sb.WriteByte(b.dialect.quoter())
+	b.sb.WriteByte(b.core.dialect.quoter())
 	b.sb.WriteString(name)
-	b.sb.WriteByte(b.dialect.quoter())
+	b.sb.WriteByte(b.core.dialect.quoter())
+}
+
+// reset 清空已经构造的内容
+// middleware 可能会提前调用 Build，所以每次 Build 都要从头开始
+func (b *builder) reset() {
+	b.sb.Reset()
+	b.args = nil
+}
+
+func (b *builder) buildColumn(name string) error {
+	return b.buildTableColumn(nil, name)
+}
+
+// buildTableColumn 按照 table 的元数据解析列
+// table 为 nil 的时候使用 b.m，table 有别名的时候输出 `alias`.`col`
+func (b *builder) buildTableColumn(table TableReference, name string) error {
+	qualifier, col, err := b.resolveColumn(table, name)
+	if err != nil {
+		return err
+	}
+	if qualifier != "" {
+		b.quote(qualifier)
+		b.sb.WriteByte('.')
+	}
+	b.quote(col)
+	return nil
+}
+
+// resolveColumn 把字段名解析成 table 里面的列名，qualifier 是列前面需要带的表名或者别名
+func (b *builder) resolveColumn(table TableReference, name string) (qualifier string, col string, err error) {
+	switch t := table.(type) {
+	case CTE:
+		// 没有别名的时候用 CTE 的名字引用
+		if t.alias != "" {
+			return subqueryColumn(t.s, t.alias, name)
+		}
+		return subqueryColumn(t.s, t.name, name)
+	case Subquery:
+		return subqueryColumn(t.s, t.alias, name)
+	}
+
+	m, err := b.modelOf(table)
+	if err != nil {
+		return "", "", err
+	}
+	fd, ok := m.FieldMap[name]
+	if !ok {
+		return "", "", err2.NewErrUnknownColumn(name)
+	}
+	if table != nil {
+		qualifier = table.tableAlias()
+		// 没有别名的表用表名限定，否则 JOIN 的时候不同表的同名列无法区分
+		if t, ok := table.(Table); ok && qualifier == "" && t.entity != nil {
+			qualifier = m.TableName
+		}
+	}
+	return qualifier, fd.ColName, nil
+}
+
+// subqueryColumn 按照子查询或者 CTE 的 SELECT 列表解析列
+func subqueryColumn(s subquerySource, qualifier string, name string) (string, string, error) {
+	col, ok := s.subqueryColumn(name)
+	if !ok {
+		return "", "", err2.NewErrUnknownColumn(name)
+	}
+	return qualifier, col, nil
+}
+
+func (b *builder) modelOf(table TableReference) (*model.Model, error) {
+	switch t := table.(type) {
+	case nil:
+		return b.m, nil
+	case Table:
+		if t.entity == nil {
+			return b.m, nil
+		}
+		return b.r.Get(t.entity)
+	default:
+		return nil, err2.NewErrUnsupportedTable(table)
+	}
+}
+
+// buildTable 构造 FROM 后面的部分
+func (b *builder) buildTable(table TableReference) error {
+	switch t := table.(type) {
+	case nil:
+		b.quote(b.m.TableName)
+	case Table:
+		if t.entity == nil {
+			// 处理 db.table_name 的情况
+			segs := strings.SplitN(t.name, ".", 2)
+			b.quote(segs[0])
+			if len(segs) == 2 {
+				b.sb.WriteByte('.')
+				b.quote(segs[1])
+			}
+		} else {
+			m, err := b.r.Get(t.entity)
+			if err != nil {
+				return err
+			}
+			b.quote(m.TableName)
+		}
+		if t.alias != "" {
+			b.sb.WriteString(" AS ")
+			b.quote(t.alias)
+		}
+	case CTE:
+		b.quote(t.name)
+		if t.alias != "" {
+			b.sb.WriteString(" AS ")
+			b.quote(t.alias)
+		}
+	case Join:
+		return b.buildJoin(t)
+	case Subquery:
+		// 作为表使用的子查询必须有别名，MySQL 和 Postgres 都不允许省略
+		if t.alias == "" {
+			return err2.ErrSubqueryWithoutAlias
+		}
+		if err := b.buildSubquery(t); err != nil {
+			return err
+		}
+		b.sb.WriteString(" AS ")
+		b.quote(t.alias)
+	default:
+		return err2.NewErrUnsupportedTable(table)
+	}
+	return nil
+}
+
+// buildSubquery 把子查询的 SQL 和参数按顺序拼接进来
+func (b *builder) buildSubquery(sub Subquery) error {
+	q, err := sub.s.buildSubquery(b.argOffset + len(b.args))
+	if err != nil {
+		return err
+	}
+	b.sb.WriteByte('(')
+	b.sb.WriteString(q.SQL)
+	b.sb.WriteByte(')')
+	b.addArgs(q.Args...)
+	return nil
+}
+
+func (b *builder) buildJoin(j Join) error {
+	if err := b.buildTable(j.left); err != nil {
+		return err
+	}
+	b.sb.WriteByte(' ')
+	b.sb.WriteString(j.typ)
+	b.sb.WriteByte(' ')
+
+	// 右边也是 Join 的时候需要括号
+	_, ok := j.right.(Join)
+	if ok {
+		b.sb.WriteByte('(')
+	}
+	if err := b.buildTable(j.right); err != nil {
+		return err
+	}
+	if ok {
+		b.sb.WriteByte(')')
+	}
+
+	if len(j.on) > 0 {
+		b.sb.WriteString(" ON ")
+		return b.buildPredicates(j.on)
+	}
+
+	if len(j.using) > 0 {
+		// USING 里面的列不能带表名，按照右边的表来解析
+		b.sb.WriteString(" USING (")
+		for i, name := range j.using {
+			if i > 0 {
+				b.sb.WriteByte(',')
+			}
+			_, col, err := b.resolveColumn(j.right, name)
+			if err != nil {
+				return err
+			}
+			b.quote(col)
+		}
+		b.sb.WriteByte(')')
+	}
+	return nil
+}
+
+// buildOrderBy 构造 ORDER BY
+// 按照列名排序的时候由 column 负责解析和输出，不同的语句解析的规则不一样
+func (b *builder) buildOrderBy(bys []OrderBy, column func(col string) error) error {
+	if len(bys) == 0 {
+		return nil
+	}
+	b.sb.WriteString(" ORDER BY ")
+	return b.buildOrderByItems(bys, column)
+}
+
+// buildOrderByItems 构造 ORDER BY 后面的部分
+func (b *builder) buildOrderByItems(bys []OrderBy, column func(col string) error) error {
+	for i, by := range bys {
+		if i > 0 {
+			b.sb.WriteByte(',')
+		}
+		var err error
+		if by.expr != nil {
+			err = b.buildExpression(by.expr)
+		} else {
+			err = column(by.col)
+		}
+		if err != nil {
+			return err
+		}
+		b.sb.WriteString(" " + by.order)
+		if by.nulls != "" {
+			if !b.core.dialect.supportNullsOrder() {
+				return err2.NewErrUnsupportedClause("NULLS " + by.nulls)
+			}
+			b.sb.WriteString(" NULLS " + by.nulls)
+		}
+	}
+	return nil
+}
+
+// parameter 写入占位符并记录参数
+// 占位符的形式由方言决定，例如 MySQL 的 ? 和 Postgres 的 $1
+func (b *builder) parameter(arg any) {
+	b.addArgs(arg)
+	b.sb.WriteString(b.core.dialect.placeholder(b.argOffset + len(b.args)))
+}
+
+func (b *builder) addArgs(args ...any) {
+	if len(args) == 0 {
+		return
+	}
+	if b.args == nil {
+		b.args = make([]any, 0, 8)
+	}
+	b.args = append(b.args, args...)
+}
+
+func (b *builder) buildPredicates(ps []Predicate) error {
+	p := ps[0]
+	for i := 1; i < len(ps); i++ {
+		p = p.And(ps[i])
+	}
+	return b.buildExpression(p)
+}
+
+func (b *builder) buildExpression(expression Expression) error {
+	switch expr := expression.(type) {
+	case nil:
+		return nil
+	case Column:
+		return b.buildTableColumn(expr.table, expr.name)
+	case Value:
+		// 窗口函数只能出现在 SELECT 列表里面，不能作为参数
+		if _, ok := expr.val.(Window); ok {
+			return err2.NewErrUnsupportedExpressionType(expr.val)
+		}
+		b.parameter(expr.val)
+	case RawExpr:
+		return b.buildRaw(expr)
+	case Subquery:
+		return b.buildSubquery(expr)
+	case tuple:
+		b.sb.WriteByte('(')
+		for i, e := range expr {
+			if i > 0 {
+				b.sb.WriteByte(',')
+			}
+			if err := b.buildExpression(e); err != nil {
+				return err
+			}
+		}
+		b.sb.WriteByte(')')
+	case Aggregate:
+		b.sb.WriteString(expr.fn)
+		b.sb.WriteByte('(')
+		if expr.distinct {
+			b.sb.WriteString("DISTINCT ")
+		}
+		if expr.arg == "*" {
+			b.sb.WriteByte('*')
+		} else if err := b.buildColumn(expr.arg); err != nil {
+			return err
+		}
+		b.sb.WriteByte(')')
+	case MathExpr:
+		if err := b.buildMathOperand(expr.left); err != nil {
+			return err
+		}
+		b.sb.WriteByte(' ')
+		b.sb.WriteString(expr.op.String())
+		b.sb.WriteByte(' ')
+		return b.buildMathOperand(expr.right)
+	case FuncExpr:
+		b.sb.WriteString(expr.name)
+		b.sb.WriteByte('(')
+		for i, arg := range expr.args {
+			if i > 0 {
+				b.sb.WriteByte(',')
+			}
+			if err := b.buildExpression(arg); err != nil {
+				return err
+			}
+		}
+		b.sb.WriteByte(')')
+	case Predicate:
+		return b.buildPredicate(expr)
+	default:
+		return err2.NewErrUnsupportedExpressionType(expr)
+	}
+	return nil
+}
+
+func (b *builder) buildPredicate(p Predicate) error {
+	switch p.op {
+	case "":
+		// RawExpr.AsPredicate，只有 left
+		if p.left == nil {
+			return err2.ErrEmptyPredicate
+		}
+		return b.buildExpression(p.left)
+	case opExists, opNotExists:
+		b.sb.WriteString(p.op.String())
+		b.sb.WriteByte(' ')
+		return b.buildExpression(p.right)
+	case opIN, opNotIN:
+		if sub, ok := p.right.(Subquery); ok {
+			if err := b.buildSubExpression(p.left); err != nil {
+				return err
+			}
+			b.sb.WriteByte(' ')
+			b.sb.WriteString(p.op.String())
+			b.sb.WriteByte(' ')
+			return b.buildSubquery(sub)
+		}
+		vs, ok := p.right.(values)
+		if !ok {
+			return err2.NewErrUnsupportedExpressionType(p.right)
+		}
+		vals := vs.vals
+		// IN () 不是合法的 SQL，直接替换为恒假或者恒真
+		// 左边虽然不输出，但是还是要校验
+		if len(vals) == 0 {
+			if err := b.checkExpression(p.left); err != nil {
+				return err
+			}
+			if p.op == opIN {
+				b.sb.WriteString("1=0")
+			} else {
+				b.sb.WriteString("1=1")
+			}
+			return nil
+		}
+		if err := b.buildSubExpression(p.left); err != nil {
+			return err
+		}
+		b.sb.WriteByte(' ')
+		b.sb.WriteString(p.op.String())
+		b.sb.WriteString(" (")
+		for i, val := range vals {
+			if i > 0 {
+				b.sb.WriteByte(',')
+			}
+			b.parameter(val)
+		}
+		b.sb.WriteByte(')')
+	case opBETWEEN:
+		vs, ok := p.right.(values)
+		if !ok || len(vs.vals) != 2 {
+			return err2.NewErrUnsupportedExpressionType(p.right)
+		}
+		vals := vs.vals
+		if err := b.buildSubExpression(p.left); err != nil {
+			return err
+		}
+		b.sb.WriteString(" BETWEEN ")
+		b.parameter(vals[0])
+		b.sb.WriteString(" AND ")
+		b.parameter(vals[1])
+	case opIsNull, opIsNotNull:
+		if err := b.buildSubExpression(p.left); err != nil {
+			return err
+		}
+		b.sb.WriteByte(' ')
+		b.sb.WriteString(p.op.String())
+	default:
+		if err := b.buildSubExpression(p.left); err != nil {
+			return err
+		}
+		if p.op != opNOT {
+			b.sb.WriteByte(' ')
+		}
+
+		b.sb.WriteString(p.op.String())
+		b.sb.WriteByte(' ')
+
+		return b.buildSubExpression(p.right)
+	}
+	return nil
+}
+
+// buildWindow 构造 fn OVER (PARTITION BY ... ORDER BY ...)
+func (b *builder) buildWindow(w Window) error {
+	if err := b.buildExpression(w.fn); err != nil {
+		return err
+	}
+	b.sb.WriteString(" OVER (")
+	if len(w.partitionBy) > 0 {
+		b.sb.WriteString("PARTITION BY ")
+		for i, c := range w.partitionBy {
+			if i > 0 {
+				b.sb.WriteByte(',')
+			}
+			if err := b.buildExpression(c); err != nil {
+				return err
+			}
+		}
+	}
+	if len(w.orderBy) > 0 {
+		if len(w.partitionBy) > 0 {
+			b.sb.WriteByte(' ')
+		}
+		b.sb.WriteString("ORDER BY ")
+		if err := b.buildOrderByItems(w.orderBy, b.buildColumn); err != nil {
+			return err
+		}
+	}
+	b.sb.WriteByte(')')
+	return nil
+}
+
+// buildRaw 输出原生 SQL，其中的 ? 按照方言转换为占位符
+// 引号里面的 ? 不是占位符，原样输出；占位符的个数必须和参数的个数一致
+func (b *builder) buildRaw(r RawExpr) error {
+	var quote byte
+	cnt := 0
+	idx := b.argOffset + len(b.args)
+	for i := 0; i < len(r.raw); i++ {
+		c := r.raw[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '?':
+			cnt++
+			b.sb.WriteString(b.core.dialect.placeholder(idx + cnt))
+			continue
+		}
+		b.sb.WriteByte(c)
+	}
+	if cnt != len(r.args) {
+		return err2.NewErrRawArgsMismatch(r.raw, cnt, len(r.args))
+	}
+	b.addArgs(r.args...)
+	return nil
+}
+
+// checkExpression 只校验表达式，不输出任何内容
+func (b *builder) checkExpression(e Expression) error {
+	tmp := &builder{
+		m:    b.m,
+		core: b.core,
+	}
+	return tmp.buildExpression(e)
+}
+
+// buildMathOperand 嵌套的算术表达式需要括号来保证优先级
+func (b *builder) buildMathOperand(e Expression) error {
+	_, ok := e.(MathExpr)
+	if ok {
+		b.sb.WriteByte('(')
+	}
+	if err := b.buildExpression(e); err != nil {
+		return err
+	}
+	if ok {
+		b.sb.WriteByte(')')
+	}
+	return nil
+}
+
+// buildAssignValue 构造 SET 和 ON DUPLICATE KEY 中的值
+// 值本身是表达式的时候直接输出，例如 Raw("`age` + ?", 1)，否则作为参数
+func (b *builder) buildAssignValue(val any) error {
+	if e, ok := val.(Expression); ok {
+		return b.buildExpression(e)
+	}
+	b.parameter(val)
+	return nil
+}
+
+// buildSubExpression 子谓词需要用括号括起来
+func (b *builder) buildSubExpression(e Expression) error {
+	_, ok := e.(Predicate)
+	if ok {
+		b.sb.WriteByte('(')
+	}
+	if err := b.buildExpression(e); err != nil {
+		return err
+	}
+	if ok {
+		b.sb.WriteByte(')')
+	}
+	return nil
 }
@@ -0,0 +1,86 @@
+package go_orm
+
+// CTE 公用表表达式，也就是 WITH 定义的命名子查询
+// 需要通过 Selector.With 或者 Selector.WithRecursive 定义之后才能作为表使用
+type CTE struct {
+	name  string
+	s     subquerySource
+	alias string
+}
+
+// With 把 sub 定义为名字是 name 的 CTE
+// 递归的 CTE 里面可以用 TableNamed(name) 引用自己
+func With(name string, sub SubqueryBuilder) CTE {
+	return CTE{
+		name: name,
+		s:    sub.subquery().s,
+	}
+}
+
+func (c CTE) tableAlias() string {
+	return c.alias
+}
+
+func (c CTE) As(alias string) CTE {
+	c.alias = alias
+	return c
+}
+
+// C 引用 CTE 的列，name 可以是 CTE 中的别名或者字段名
+func (c CTE) C(name string) Column {
+	return Column{
+		table: c,
+		name:  name,
+	}
+}
+
+func (c CTE) Join(right TableReference) *JoinBuilder {
+	return newJoinBuilder(c, right, "JOIN")
+}
+
+func (c CTE) LeftJoin(right TableReference) *JoinBuilder {
+	return newJoinBuilder(c, right, "LEFT JOIN")
+}
+
+func (c CTE) RightJoin(right TableReference) *JoinBuilder {
+	return newJoinBuilder(c, right, "RIGHT JOIN")
+}
+
+// With 定义 CTE，CTE 的参数排在主查询的参数前面
+// With 和 WithRecursive 以最后一次调用为准，需要同时定义多个 CTE 的时候一次传入
+func (s *Selector[T]) With(ctes ...CTE) *Selector[T] {
+	s.ctes = ctes
+	s.recursive = false
+	return s
+}
+
+// WithRecursive 定义递归的 CTE，例如遍历树形结构
+// 和 With 一样会覆盖之前定义的 CTE
+func (s *Selector[T]) WithRecursive(ctes ...CTE) *Selector[T] {
+	s.ctes = ctes
+	s.recursive = true
+	return s
+}
+
+// buildWith 构造 WITH [RECURSIVE] name AS (...)
+func (b *builder) buildWith(ctes []CTE, recursive bool) error {
+	if len(ctes) == 0 {
+		return nil
+	}
+	b.sb.WriteString("WITH ")
+	if recursive {
+		b.sb.WriteString("RECURSIVE ")
+	}
+	for i, c := range ctes {
+		if i > 0 {
+			b.sb.WriteByte(',')
+		}
+		b.quote(c.name)
+		b.sb.WriteString(" AS ")
+		if err := b.buildSubquery(Subquery{s: c.s}); err != nil {
+			return err
+		}
+	}
+	b.sb.WriteByte(' ')
+	return nil
+}
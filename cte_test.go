@@ -0,0 +1,163 @@
+package go_orm
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	err2 "go-orm/internal/err"
+	"testing"
+)
+
+type Category struct {
+	Id       int64
+	ParentId int64
+	Name     string
+}
+
+func TestSelector_With(t *testing.T) {
+	db := memoryDB()
+	tests := []struct {
+		name    string
+		s       QueryBuilder
+		want    *Query
+		wantErr error
+	}{
+		{
+			name: "with",
+			s: func() QueryBuilder {
+				recent := With("recent", NewSelector[Order](db).Where(C("Id").GT(100)))
+				return NewSelector[Order](db).With(recent).FromTable(recent).Where(C("UsingCol1").EQ("a"))
+			}(),
+			want: &Query{
+				SQL:  "WITH `recent` AS (SELECT * FROM `order` WHERE `id` > ?) SELECT * FROM `recent` WHERE `using_col1` = ?;",
+				Args: []any{100, "a"},
+			},
+		},
+		{
+			name: "column by alias",
+			s: func() QueryBuilder {
+				cnt := With("cnt", NewSelector[OrderDetail](db).Select(C("OrderId"), Count("ItemId").As("total")).
+					GroupBy(C("OrderId")))
+				return NewSelector[OrderDetail](db).With(cnt).Select(cnt.C("OrderId"), cnt.C("total")).
+					FromTable(cnt).Where(cnt.C("total").GT(2))
+			}(),
+			want: &Query{
+				SQL: "WITH `cnt` AS (SELECT `order_id`,COUNT(`item_id`) as `total` FROM `order_detail` GROUP BY `order_id`)" +
+					" SELECT `cnt`.`order_id`,`cnt`.`total` FROM `cnt` WHERE `cnt`.`total` > ?;",
+				Args: []any{2},
+			},
+		},
+		{
+			name: "multiple with join",
+			s: func() QueryBuilder {
+				o := With("o", NewSelector[Order](db).Where(C("Id").LT(10))).As("t1")
+				d := With("d", NewSelector[OrderDetail](db).Where(C("ItemId").EQ(3))).As("t2")
+				return NewSelector[Order](db).With(o, d).Select(o.C("Id"), d.C("ItemId")).
+					FromTable(o.Join(d).On(o.C("Id").EQ(d.C("OrderId")))).Where(o.C("Id").GT(1))
+			}(),
+			want: &Query{
+				SQL: "WITH `o` AS (SELECT * FROM `order` WHERE `id` < ?),`d` AS (SELECT * FROM `order_detail` WHERE `item_id` = ?)" +
+					" SELECT `t1`.`id`,`t2`.`item_id` FROM `o` AS `t1` JOIN `d` AS `t2` ON `t1`.`id` = `t2`.`order_id` WHERE `t1`.`id` > ?;",
+				Args: []any{10, 3, 1},
+			},
+		},
+		{
+			name: "recursive",
+			s: func() QueryBuilder {
+				c := TableOf[Category]().As("c")
+				t := TableNamed("tree").As("t")
+				tree := With("tree", NewSelector[Category](db).Where(C("Id").EQ(1)).
+					UnionAll(NewSelector[Category](db).Select(c.C("Id"), c.C("ParentId"), c.C("Name")).
+						FromTable(c.Join(t).On(c.C("ParentId").EQ(t.C("Id"))))))
+				return NewSelector[Category](db).WithRecursive(tree).FromTable(tree).OrderBy(Asc("Id"))
+			}(),
+			want: &Query{
+				SQL: "WITH RECURSIVE `tree` AS (SELECT * FROM `category` WHERE `id` = ? UNION ALL" +
+					" SELECT `c`.`id`,`c`.`parent_id`,`c`.`name` FROM `category` AS `c` JOIN `tree` AS `t` ON `c`.`parent_id` = `t`.`id`)" +
+					" SELECT * FROM `tree` ORDER BY `id` ASC;",
+				Args: []any{1},
+			},
+		},
+		{
+			name: "last with wins",
+			s: func() QueryBuilder {
+				old := With("old", NewSelector[Order](db).Where(C("Id").LT(10)))
+				recent := With("recent", NewSelector[Order](db).Where(C("Id").GT(100)))
+				return NewSelector[Order](db).WithRecursive(old).With(recent).FromTable(recent)
+			}(),
+			want: &Query{
+				SQL:  "WITH `recent` AS (SELECT * FROM `order` WHERE `id` > ?) SELECT * FROM `recent`;",
+				Args: []any{100},
+			},
+		},
+		{
+			name: "unknown column",
+			s: func() QueryBuilder {
+				recent := With("recent", NewSelector[Order](db).Select(C("Id")))
+				return NewSelector[Order](db).With(recent).Select(recent.C("UsingCol1")).FromTable(recent)
+			}(),
+			wantErr: err2.NewErrUnknownColumn("UsingCol1"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.Build()
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSelector_With_Postgres(t *testing.T) {
+	db := memoryDB(DBWithDialect(Postgres))
+	recent := With("recent", NewSelector[Order](db).Where(C("Id").GT(100)).Limit(5))
+	q, err := NewSelector[Order](db).With(recent).FromTable(recent).Where(C("UsingCol1").EQ("a")).Limit(1).Build()
+	require.NoError(t, err)
+	assert.Equal(t, &Query{
+		SQL: `WITH "recent" AS (SELECT * FROM "order" WHERE "id" > $1 LIMIT $2)` +
+			` SELECT * FROM "recent" WHERE "using_col1" = $3 LIMIT $4;`,
+		Args: []any{100, int32(5), "a", int32(1)},
+	}, q)
+}
+
+func TestSelector_WithRecursive_sqlite(t *testing.T) {
+	db := sqliteDB(t)
+	ctx := context.Background()
+	_, err := db.db.Exec("CREATE TABLE `category`(" +
+		"`id` INTEGER PRIMARY KEY," +
+		"`parent_id` INTEGER NOT NULL," +
+		"`name` TEXT NOT NULL)")
+	require.NoError(t, err)
+
+	res := NewInserter[Category](db).Values(
+		&Category{Id: 1, ParentId: 0, Name: "root"},
+		&Category{Id: 2, ParentId: 1, Name: "a"},
+		&Category{Id: 3, ParentId: 2, Name: "a1"},
+		&Category{Id: 4, ParentId: 0, Name: "other"},
+		&Category{Id: 5, ParentId: 1, Name: "b"},
+	).Exec(ctx)
+	require.NoError(t, res.(Result).Err())
+
+	c := TableOf[Category]().As("c")
+	t1 := TableNamed("tree").As("t")
+	tree := With("tree", NewSelector[Category](db).Where(C("Id").EQ(1)).
+		UnionAll(NewSelector[Category](db).Select(c.C("Id"), c.C("ParentId"), c.C("Name")).
+			FromTable(c.Join(t1).On(c.C("ParentId").EQ(t1.C("Id"))))))
+
+	got, err := NewSelector[Category](db).WithRecursive(tree).FromTable(tree).
+		Where(C("Id").NEQ(5)).OrderBy(Asc("Id")).GetMulti(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []*Category{
+		{Id: 1, ParentId: 0, Name: "root"},
+		{Id: 2, ParentId: 1, Name: "a"},
+		{Id: 3, ParentId: 2, Name: "a1"},
+	}, got)
+
+	page, err := NewSelector[Category](db).WithRecursive(tree).FromTable(tree).OrderBy(Asc("Id")).Paginate(ctx, 2, 3)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), page.Total)
+	assert.Equal(t, []*Category{{Id: 5, ParentId: 1, Name: "b"}}, page.Items)
+}
@@ -0,0 +1,241 @@
+package go_orm
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	err2 "go-orm/internal/err"
+	"go-orm/internal/model"
+	"reflect"
+)
+
+// PageResult 游标分页的结果
+// NextCursor 为空说明已经没有下一页了
+type PageResult[T any] struct {
+	Items      []*T
+	NextCursor string
+}
+
+// tuple 行构造器，例如 (`a`,`b`)
+type tuple []Expression
+
+func (t tuple) expr() {}
+
+type cursorKey struct {
+	col  Column
+	fd   *model.Field
+	desc bool
+}
+
+// After 从 cursor 对应的行之后开始读取
+// cursor 来自上一页的 PageResult.NextCursor，为空的时候从头开始
+func (s *Selector[T]) After(cursor string) *Selector[T] {
+	s.after = cursor
+	return s
+}
+
+// Seek 游标分页，读取 After 之后的 size 行
+// 排序的列来自 OrderBy，必须能够唯一确定一行并且不能为 NULL，
+// 一般最后一列用主键，例如 OrderBy(Desc("CreateTime"), Desc("Id"))
+// 相比 Offset，翻页再深也只需要扫描 size 行
+func (s *Selector[T]) Seek(ctx context.Context, size int32) (*PageResult[T], error) {
+	if size <= 0 {
+		return nil, ErrInvalidPageSize
+	}
+	if len(s.orderBy) == 0 {
+		return nil, ErrCursorWithoutOrderBy
+	}
+
+	// 多读一行，用来判断还有没有下一页
+	// 在副本上修改 LIMIT，s 还可以继续用来读取其它页
+	q := s.clone()
+	q.limit = size + 1
+	items, err := q.GetMulti(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &PageResult[T]{
+		Items: items,
+	}
+	if len(items) > int(size) {
+		res.Items = items[:size]
+		res.NextCursor, err = q.encodeCursor(res.Items[size-1])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// cursorKeys 排序的列，只能是列，并且需要出现在 SELECT 里面
+func (s *Selector[T]) cursorKeys() ([]cursorKey, error) {
+	if len(s.orderBy) == 0 {
+		return nil, ErrCursorWithoutOrderBy
+	}
+	keys := make([]cursorKey, 0, len(s.orderBy))
+	for _, by := range s.orderBy {
+		col := C(by.col)
+		if by.expr != nil {
+			c, ok := by.expr.(Column)
+			if !ok {
+				return nil, err2.NewErrUnsupportedExpressionType(by.expr)
+			}
+			col = c
+		}
+		fd, err := s.cursorField(col)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, cursorKey{
+			col:  col,
+			fd:   fd,
+			desc: by.order == "DESC",
+		})
+	}
+	return keys, nil
+}
+
+// cursorField 排序的列在结果集中对应的 T 的字段
+// 列按照自己的表来解析，然后按照 SELECT 里面的别名或者列名找到 T 的字段
+func (s *Selector[T]) cursorField(col Column) (*model.Field, error) {
+	qualifier, name, err := s.resolveColumn(col.table, col.name)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.columns) > 0 {
+		selected := false
+		for _, sel := range s.columns {
+			c, ok := sel.(Column)
+			if !ok {
+				continue
+			}
+			q, n, err := s.resolveColumn(c.table, c.name)
+			if err != nil {
+				return nil, err
+			}
+			if q == qualifier && n == name {
+				if c.alias != "" {
+					name = c.alias
+				}
+				selected = true
+				break
+			}
+		}
+		if !selected {
+			return nil, ErrCursorKeyNotSelected
+		}
+	}
+	fd, ok := s.m.ColumnMap[name]
+	if !ok {
+		return nil, ErrCursorKeyNotSelected
+	}
+	return fd, nil
+}
+
+// cursorPredicate 把游标转换为 WHERE 条件
+// 排序方向一致的时候使用行比较，例如 (`a`,`b`) > (?,?)，
+// 不一致的时候展开为 (`a` > ?) OR ((`a` = ?) AND (`b` < ?))
+func (s *Selector[T]) cursorPredicate() (Predicate, error) {
+	keys, err := s.cursorKeys()
+	if err != nil {
+		return Predicate{}, err
+	}
+	vals, err := decodeCursor(s.after, keys)
+	if err != nil {
+		return Predicate{}, err
+	}
+
+	sameOrder := true
+	for _, k := range keys {
+		if k.desc != keys[0].desc {
+			sameOrder = false
+			break
+		}
+	}
+
+	if sameOrder {
+		var cmp op = opGT
+		if keys[0].desc {
+			cmp = opLT
+		}
+		if len(keys) == 1 {
+			return newPredicate(keys[0].col, cmp, vals[0]), nil
+		}
+		cols := make(tuple, 0, len(keys))
+		args := make(tuple, 0, len(keys))
+		for i, k := range keys {
+			cols = append(cols, k.col)
+			args = append(args, Value{val: vals[i]})
+		}
+		return Predicate{
+			left:  cols,
+			op:    cmp,
+			right: args,
+		}, nil
+	}
+
+	var res Predicate
+	for i, k := range keys {
+		var p Predicate
+		if k.desc {
+			p = k.col.LT(vals[i])
+		} else {
+			p = k.col.GT(vals[i])
+		}
+		for j := i - 1; j >= 0; j-- {
+			p = keys[j].col.EQ(vals[j]).And(p)
+		}
+		if i == 0 {
+			res = p
+		} else {
+			res = res.Or(p)
+		}
+	}
+	return res, nil
+}
+
+// encodeCursor 把排序的列的值编码为游标
+// 使用 JSON 加 base64，对于调用者来说是不透明的
+func (s *Selector[T]) encodeCursor(t *T) (string, error) {
+	keys, err := s.cursorKeys()
+	if err != nil {
+		return "", err
+	}
+	val := s.valCreator(t, s.m)
+	vals := make([]any, 0, len(keys))
+	for _, k := range keys {
+		v, err := val.Field(k.fd.GoName)
+		if err != nil {
+			return "", err
+		}
+		vals = append(vals, v)
+	}
+	data, err := json.Marshal(vals)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor 按照字段的类型还原游标中的值
+// 避免 JSON 把 int64 解析成 float64 丢失精度
+func decodeCursor(cursor string, keys []cursorKey) ([]any, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var raws []json.RawMessage
+	if err = json.Unmarshal(data, &raws); err != nil || len(raws) != len(keys) {
+		return nil, ErrInvalidCursor
+	}
+	vals := make([]any, 0, len(keys))
+	for i, k := range keys {
+		v := reflect.New(k.fd.Typ)
+		if err = json.Unmarshal(raws[i], v.Interface()); err != nil {
+			return nil, ErrInvalidCursor
+		}
+		vals = append(vals, v.Elem().Interface())
+	}
+	return vals, nil
+}
@@ -0,0 +1,176 @@
+package go_orm
+
+import (
+	"context"
+	"encoding/base64"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	err2 "go-orm/internal/err"
+	"testing"
+)
+
+func TestSelector_After(t *testing.T) {
+	db := memoryDB()
+	cursor := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+	tests := []struct {
+		name    string
+		s       QueryBuilder
+		want    *Query
+		wantErr error
+	}{
+		{
+			name: "single column",
+			s:    NewSelector[TestModel](db).OrderBy(Asc("Id")).After(cursor(`[10]`)).Limit(5),
+			want: &Query{
+				SQL:  "SELECT * FROM `test_model` WHERE `id` > ? ORDER BY `id` ASC LIMIT ?;",
+				Args: []any{int64(10), int32(5)},
+			},
+		},
+		{
+			name: "same order",
+			s: NewSelector[TestModel](db).Where(C("FirstName").EQ("liu")).
+				OrderBy(Desc("Age"), Desc("Id")).After(cursor(`[18,10]`)),
+			want: &Query{
+				SQL:  "SELECT * FROM `test_model` WHERE (`first_name` = ?) AND ((`age`,`id`) < (?,?)) ORDER BY `age` DESC,`id` DESC;",
+				Args: []any{"liu", int8(18), int64(10)},
+			},
+		},
+		{
+			name: "mixed order",
+			s: NewSelector[TestModel](db).
+				OrderBy(Asc("FirstName"), Desc("Age"), Asc("Id")).After(cursor(`["liu",18,10]`)),
+			want: &Query{
+				SQL: "SELECT * FROM `test_model` WHERE ((`first_name` > ?) OR ((`first_name` = ?) AND (`age` < ?)))" +
+					" OR ((`first_name` = ?) AND ((`age` = ?) AND (`id` > ?))) ORDER BY `first_name` ASC,`age` DESC,`id` ASC;",
+				Args: []any{"liu", "liu", int8(18), "liu", int8(18), int64(10)},
+			},
+		},
+		{
+			name: "qualified column",
+			s: func() QueryBuilder {
+				t1 := TableOf[TestModel]().As("t1")
+				return NewSelector[TestModel](db).FromTable(t1).OrderBy(t1.C("Id").Desc()).After(cursor(`[10]`))
+			}(),
+			want: &Query{
+				SQL:  "SELECT * FROM `test_model` AS `t1` WHERE `t1`.`id` < ? ORDER BY `t1`.`id` DESC;",
+				Args: []any{int64(10)},
+			},
+		},
+		{
+			name: "joined column",
+			s: func() QueryBuilder {
+				t1 := TableOf[TestModel]().As("t1")
+				t2 := TableOf[Order]().As("t2")
+				return NewSelector[TestModel](db).Select(t1.C("Id"), t2.C("UsingCol1").As("first_name")).
+					FromTable(t1.Join(t2).On(t1.C("Id").EQ(t2.C("Id")))).
+					OrderBy(t2.C("UsingCol1").Asc(), t1.C("Id").Asc()).After(cursor(`["liu",10]`))
+			}(),
+			want: &Query{
+				SQL: "SELECT `t1`.`id`,`t2`.`using_col1` as `first_name` FROM `test_model` AS `t1` JOIN `order` AS `t2`" +
+					" ON `t1`.`id` = `t2`.`id` WHERE (`t2`.`using_col1`,`t1`.`id`) > (?,?) ORDER BY `t2`.`using_col1` ASC,`t1`.`id` ASC;",
+				Args: []any{"liu", int64(10)},
+			},
+		},
+		{
+			name:    "key not selected",
+			s:       NewSelector[TestModel](db).Select(C("Id")).OrderBy(Asc("Age"), Asc("Id")).After(cursor(`[18,10]`)),
+			wantErr: ErrCursorKeyNotSelected,
+		},
+		{
+			name:    "without order by",
+			s:       NewSelector[TestModel](db).After(cursor(`[10]`)),
+			wantErr: ErrCursorWithoutOrderBy,
+		},
+		{
+			name:    "invalid cursor",
+			s:       NewSelector[TestModel](db).OrderBy(Asc("Id")).After("abc!"),
+			wantErr: ErrInvalidCursor,
+		},
+		{
+			name:    "cursor mismatch",
+			s:       NewSelector[TestModel](db).OrderBy(Asc("Age"), Asc("Id")).After(cursor(`[10]`)),
+			wantErr: ErrInvalidCursor,
+		},
+		{
+			name:    "order by expression",
+			s:       NewSelector[TestModel](db).OrderBy(Raw("RAND()").Asc()).After(cursor(`[10]`)),
+			wantErr: err2.NewErrUnsupportedExpressionType(Raw("RAND()")),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.Build()
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSelector_Seek(t *testing.T) {
+	db := sqliteDB(t)
+	ctx := context.Background()
+	res := NewInserter[TestModel](db).Values(
+		&TestModel{Id: 1, FirstName: "a", Age: 20},
+		&TestModel{Id: 2, FirstName: "b", Age: 18},
+		&TestModel{Id: 3, FirstName: "c", Age: 20},
+		&TestModel{Id: 4, FirstName: "d", Age: 30},
+		&TestModel{Id: 5, FirstName: "e", Age: 18},
+	).Exec(ctx)
+	require.NoError(t, res.(Result).Err())
+
+	_, err := NewSelector[TestModel](db).OrderBy(Asc("Id")).Seek(ctx, 0)
+	assert.Equal(t, ErrInvalidPageSize, err)
+
+	// Seek 不会修改原来的查询
+	s := NewSelector[TestModel](db).OrderBy(Asc("Id"))
+	page, err := s.Seek(ctx, 2)
+	require.NoError(t, err)
+	assert.Len(t, page.Items, 2)
+	all, err := s.GetMulti(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, 5)
+
+	testCases := []struct {
+		name    string
+		orderBy []OrderBy
+		want    [][]int64
+	}{
+		{
+			name:    "same order",
+			orderBy: []OrderBy{Desc("Age"), Desc("Id")},
+			want:    [][]int64{{4, 3}, {1, 5}, {2}},
+		},
+		{
+			name:    "mixed order",
+			orderBy: []OrderBy{Asc("Age"), Desc("Id")},
+			want:    [][]int64{{5, 2}, {3, 1}, {4}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				cursor string
+				pages  [][]int64
+			)
+			for {
+				page, err := NewSelector[TestModel](db).OrderBy(tc.orderBy...).After(cursor).Seek(ctx, 2)
+				require.NoError(t, err)
+				ids := make([]int64, 0, len(page.Items))
+				for _, item := range page.Items {
+					ids = append(ids, item.Id)
+				}
+				pages = append(pages, ids)
+				if page.NextCursor == "" {
+					break
+				}
+				cursor = page.NextCursor
+			}
+			assert.Equal(t, tc.want, pages)
+		})
+	}
+}
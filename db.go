@@ -50,7 +50,7 @@ func (db *DB) Begin(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
 		return nil, err
 	}
 
-	return &Tx{tx: tx}, nil
+	return &Tx{tx: tx, core: db.core}, nil
 }
 
 func (db *DB) getCore() core {
@@ -58,11 +58,11 @@ func (db *DB) getCore() core {
 }
 
 func (db *DB) queryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
-	return db.db.QueryContext(ctx, query, args)
+	return db.db.QueryContext(ctx, query, args...)
 }
 
-func (db *DB) exec(query string, args ...any) (sql.Result, error) {
-	return db.db.Exec(query, args)
+func (db *DB) execContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return db.db.ExecContext(ctx, query, args...)
 }
 
 func DBUseReflectValuer() DBOption {
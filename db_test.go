@@ -0,0 +1,87 @@
+package go_orm
+
+import (
+	"context"
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDB_queryContext(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+
+	db, err := OpenDB(mockDB, DBUseReflectValuer())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "first_name", "age", "last_name"})
+	rows.AddRow([]byte("1"), []byte("Liu"), []byte("18"), []byte("Quan"))
+	mock.ExpectQuery("SELECT \\* FROM `test_model` WHERE \\(`id` = \\?\\) AND \\(`age` > \\?\\);").
+		WithArgs(1, 10).
+		WillReturnRows(rows)
+
+	res, err := NewSelector[TestModel](db).Where(C("Id").EQ(1), C("Age").GT(10)).Get(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &TestModel{
+		Id:        1,
+		FirstName: "Liu",
+		Age:       18,
+		LastName:  &sql.NullString{String: "Quan", Valid: true},
+	}, res)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDB_exec(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+
+	db, err := OpenDB(mockDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mock.ExpectExec("INSERT INTO `test_model`\\(`first_name`,`age`\\)VALUES\\(\\?,\\?\\);").
+		WithArgs("liu", int8(18)).
+		WillReturnResult(sqlmock.NewResult(12, 1))
+
+	res := NewInserter[TestModel](db).Columns("FirstName", "Age").
+		Values(&TestModel{FirstName: "liu", Age: 18}).Exec(context.Background())
+	id, err := res.LastInsertId()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, int64(12), id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDB_execContext_cancel(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+
+	db, err := OpenDB(mockDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mock.ExpectExec("DELETE .*").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	res := NewDeleter[TestModel](db).Where(C("Id").EQ(1)).Exec(ctx)
+	_, err = res.RowsAffected()
+	assert.Equal(t, context.Canceled, err)
+}
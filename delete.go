@@ -0,0 +1,103 @@
+package go_orm
+
+import (
+	"context"
+	"database/sql"
+	err2 "go-orm/internal/err"
+)
+
+type Deleter[T any] struct {
+	builder
+	sess      Session
+	where     []Predicate
+	orderBy   []OrderBy
+	limit     int32
+	fullTable bool
+}
+
+func NewDeleter[T any](sess Session) *Deleter[T] {
+	c := sess.getCore()
+	return &Deleter[T]{
+		builder: builder{
+			core: c,
+		},
+		sess: sess,
+	}
+}
+
+func (d *Deleter[T]) Where(ps ...Predicate) *Deleter[T] {
+	d.where = ps
+	return d
+}
+
+// OrderBy 只有方言支持的时候才能使用，例如 MySQL
+func (d *Deleter[T]) OrderBy(ps ...OrderBy) *Deleter[T] {
+	d.orderBy = ps
+	return d
+}
+
+// Limit 只有方言支持的时候才能使用，例如 MySQL
+func (d *Deleter[T]) Limit(l int32) *Deleter[T] {
+	d.limit = l
+	return d
+}
+
+// AllowFullTable 允许不带 WHERE 条件删除全表
+// 默认情况下 Build 会拒绝这种语句
+func (d *Deleter[T]) AllowFullTable() *Deleter[T] {
+	d.fullTable = true
+	return d
+}
+
+func (d *Deleter[T]) Build() (*Query, error) {
+	if len(d.where) == 0 && !d.fullTable {
+		return nil, err2.ErrDeleteWithoutWhere
+	}
+
+	var err error
+	d.m, err = d.r.Get(new(T))
+	if err != nil {
+		return nil, err
+	}
+
+	d.reset()
+	d.sb.WriteString("DELETE FROM ")
+	d.quote(d.m.TableName)
+
+	if len(d.where) > 0 {
+		d.sb.WriteString(" WHERE ")
+		if err = d.buildPredicates(d.where); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(d.orderBy) > 0 {
+		if !d.core.dialect.supportDeleteLimit() {
+			return nil, err2.NewErrUnsupportedClause("DELETE ... ORDER BY")
+		}
+		if err = d.builder.buildOrderBy(d.orderBy, d.buildColumn); err != nil {
+			return nil, err
+		}
+	}
+
+	if d.limit > 0 {
+		if !d.core.dialect.supportDeleteLimit() {
+			return nil, err2.NewErrUnsupportedClause("DELETE ... LIMIT")
+		}
+		d.sb.WriteString(" LIMIT ")
+		d.parameter(d.limit)
+	}
+
+	d.sb.WriteByte(';')
+	return &Query{
+		SQL:  d.sb.String(),
+		Args: d.args,
+	}, nil
+}
+
+func (d *Deleter[T]) Exec(ctx context.Context) sql.Result {
+	return exec(ctx, d.sess, d.core, &QueryContext{
+		Type:    "DELETE",
+		Builder: d,
+	})
+}
@@ -0,0 +1,77 @@
+package go_orm
+
+import (
+	"github.com/stretchr/testify/assert"
+	err2 "go-orm/internal/err"
+	"testing"
+)
+
+func TestDeleter_Build(t *testing.T) {
+	db := memoryDB()
+	tests := []struct {
+		name    string
+		d       QueryBuilder
+		want    *Query
+		wantErr error
+	}{
+		{
+			name:    "no where",
+			d:       NewDeleter[TestModel](db),
+			wantErr: ErrDeleteWithoutWhere,
+		},
+		{
+			name: "full table",
+			d:    NewDeleter[TestModel](db).AllowFullTable(),
+			want: &Query{
+				SQL: "DELETE FROM `test_model`;",
+			},
+		},
+		{
+			name: "where",
+			d:    NewDeleter[TestModel](db).Where(C("Id").EQ(12)),
+			want: &Query{
+				SQL:  "DELETE FROM `test_model` WHERE `id` = ?;",
+				Args: []any{12},
+			},
+		},
+		{
+			name: "where and",
+			d:    NewDeleter[TestModel](db).Where(C("Age").GT(18), Not(C("FirstName").EQ("liu"))),
+			want: &Query{
+				SQL:  "DELETE FROM `test_model` WHERE (`age` > ?) AND (NOT (`first_name` = ?));",
+				Args: []any{18, "liu"},
+			},
+		},
+		{
+			name: "order by limit",
+			d:    NewDeleter[TestModel](db).Where(C("Age").GT(18)).OrderBy(Desc("Id")).Limit(10),
+			want: &Query{
+				SQL:  "DELETE FROM `test_model` WHERE `age` > ? ORDER BY `id` DESC LIMIT ?;",
+				Args: []any{18, int32(10)},
+			},
+		},
+		{
+			name: "raw",
+			d:    NewDeleter[TestModel](db).Where(Raw("`age` > ?", 18).AsPredicate()).OrderBy(Raw("RAND()").Asc()).Limit(1),
+			want: &Query{
+				SQL:  "DELETE FROM `test_model` WHERE `age` > ? ORDER BY RAND() ASC LIMIT ?;",
+				Args: []any{18, int32(1)},
+			},
+		},
+		{
+			name:    "unknown column",
+			d:       NewDeleter[TestModel](db).Where(C("Invalid").EQ(1)),
+			wantErr: err2.NewErrUnknownColumn("Invalid"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.d.Build()
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -2,17 +2,123 @@ package go_orm
 
 import (
 	err2 "go-orm/internal/err"
+	"go-orm/internal/model"
+	"strconv"
+)
+
+var (
+	MySQL    Dialect = &mysqlDialect{}
+	Postgres Dialect = &postgresDialect{}
+	SQLite   Dialect = &sqliteDialect{}
+)
+
+type lockMode uint8
+
+const (
+	lockModeNone lockMode = iota
+	// lockModeUpdate 排他锁，对应 FOR UPDATE
+	lockModeUpdate
+	// lockModeShare 共享锁，对应 FOR SHARE
+	lockModeShare
+)
+
+type lockWait uint8
+
+const (
+	lockWaitDefault lockWait = iota
+	// lockWaitNoWait 拿不到锁的时候直接返回错误
+	lockWaitNoWait
+	// lockWaitSkipLocked 跳过已经被锁住的行
+	lockWaitSkipLocked
+)
+
+type insertMode uint8
+
+const (
+	insertModeDefault insertMode = iota
+	// insertModeIgnore 冲突的时候忽略该行
+	insertModeIgnore
+	// insertModeReplace 冲突的时候删除旧行再插入
+	insertModeReplace
 )
 
 type Dialect interface {
 	quoter() byte
+	// placeholder 返回第 idx 个参数的占位符，idx 从 1 开始
+	placeholder(idx int) string
+	// insertInto 返回 INSERT 语句的开头，例如 INSERT INTO、REPLACE INTO
+	insertInto(mode insertMode) (string, error)
 	buildDuplicateKey(b *builder, key *OnDuplicateKey) error
+	buildReturning(b *builder, fields []*model.Field) error
+	// supportDeleteLimit DELETE 语句是否支持 ORDER BY 和 LIMIT
+	supportDeleteLimit() bool
+	// supportSetOperator 是否支持 UNION、INTERSECT 等集合操作
+	supportSetOperator(op setOperator) bool
+	// buildSetPart 构造集合操作里面需要括号的查询，例如带了 LIMIT 的查询
+	buildSetPart(b *builder, part string)
+	// supportNullsOrder ORDER BY 是否支持 NULLS FIRST 和 NULLS LAST
+	supportNullsOrder() bool
+	// buildLock 构造 SELECT 语句最后的行锁
+	buildLock(b *builder, mode lockMode, wait lockWait) error
 }
 
 // 标准sql
 type standardSQL struct {
 }
 
+func (s standardSQL) placeholder(idx int) string {
+	return "?"
+}
+
+func (s standardSQL) insertInto(mode insertMode) (string, error) {
+	switch mode {
+	case insertModeDefault:
+		return "INSERT INTO ", nil
+	case insertModeIgnore:
+		return "", err2.NewErrUnsupportedClause("INSERT IGNORE")
+	default:
+		return "", err2.NewErrUnsupportedClause("REPLACE")
+	}
+}
+
+func (s standardSQL) buildReturning(b *builder, fields []*model.Field) error {
+	return err2.NewErrUnsupportedClause("RETURNING")
+}
+
+func (s standardSQL) supportDeleteLimit() bool {
+	return false
+}
+
+func (s standardSQL) supportSetOperator(op setOperator) bool {
+	return true
+}
+
+func (s standardSQL) buildSetPart(b *builder, part string) {
+	b.sb.WriteByte('(')
+	b.sb.WriteString(part)
+	b.sb.WriteByte(')')
+}
+
+func (s standardSQL) supportNullsOrder() bool {
+	return true
+}
+
+// buildLock MySQL 8 和 Postgres 的写法是一样的
+func (s standardSQL) buildLock(b *builder, mode lockMode, wait lockWait) error {
+	if mode == lockModeShare {
+		b.sb.WriteString(" FOR SHARE")
+	} else {
+		b.sb.WriteString(" FOR UPDATE")
+	}
+	switch wait {
+	case lockWaitNoWait:
+		b.sb.WriteString(" NOWAIT")
+	case lockWaitSkipLocked:
+		b.sb.WriteString(" SKIP LOCKED")
+	}
+	return nil
+}
+
 type mysqlDialect struct {
 	standardSQL
 }
@@ -21,6 +127,30 @@ func (d *mysqlDialect) quoter() byte {
 	return '`'
 }
 
+func (d *mysqlDialect) insertInto(mode insertMode) (string, error) {
+	switch mode {
+	case insertModeIgnore:
+		return "INSERT IGNORE INTO ", nil
+	case insertModeReplace:
+		return "REPLACE INTO ", nil
+	default:
+		return d.standardSQL.insertInto(mode)
+	}
+}
+
+func (d *mysqlDialect) supportDeleteLimit() bool {
+	return true
+}
+
+// supportSetOperator MySQL 8.0.31 之前不支持 INTERSECT 和 EXCEPT
+func (d *mysqlDialect) supportSetOperator(op setOperator) bool {
+	return op == setOpUnion || op == setOpUnionAll
+}
+
+func (d *mysqlDialect) supportNullsOrder() bool {
+	return false
+}
+
 func (d *mysqlDialect) buildDuplicateKey(bu *builder, odk *OnDuplicateKey) error {
 	bu.sb.WriteString(" ON DUPLICATE KEY UPDATE ")
 	for i2, assign := range odk.assigns {
@@ -35,8 +165,10 @@ func (d *mysqlDialect) buildDuplicateKey(bu *builder, odk *OnDuplicateKey) error
 			}
 
 			bu.quote(fd.ColName)
-			bu.sb.WriteString("=?")
-			bu.args = append(bu.args, a.val)
+			bu.sb.WriteByte('=')
+			if err := bu.buildAssignValue(a.val); err != nil {
+				return err
+			}
 		case Column:
 			fd, ok := bu.m.FieldMap[a.name]
 			if !ok {
@@ -46,11 +178,131 @@ func (d *mysqlDialect) buildDuplicateKey(bu *builder, odk *OnDuplicateKey) error
 			bu.sb.WriteString("=VALUES(")
 			bu.quote(fd.ColName)
 			bu.sb.WriteByte(')')
+		default:
+			return err2.NewErrUnsupportedAssignableType(assign)
 		}
 	}
 	return nil
 }
 
+type postgresDialect struct {
+	standardSQL
+}
+
+func (d *postgresDialect) quoter() byte {
+	return '"'
+}
+
+func (d *postgresDialect) placeholder(idx int) string {
+	return "$" + strconv.Itoa(idx)
+}
+
+// buildDuplicateKey 对应 ON CONFLICT (...) DO UPDATE SET
+// Postgres 要求必须指定冲突列
+func (d *postgresDialect) buildDuplicateKey(bu *builder, odk *OnDuplicateKey) error {
+	return buildOnConflict(bu, odk, "EXCLUDED.")
+}
+
+func (d *postgresDialect) buildReturning(bu *builder, fields []*model.Field) error {
+	return buildReturning(bu, fields)
+}
+
 type sqliteDialect struct {
 	standardSQL
 }
+
+func (d *sqliteDialect) quoter() byte {
+	return '"'
+}
+
+func (d *sqliteDialect) insertInto(mode insertMode) (string, error) {
+	switch mode {
+	case insertModeIgnore:
+		return "INSERT OR IGNORE INTO ", nil
+	case insertModeReplace:
+		return "INSERT OR REPLACE INTO ", nil
+	default:
+		return d.standardSQL.insertInto(mode)
+	}
+}
+
+func (d *sqliteDialect) buildDuplicateKey(bu *builder, odk *OnDuplicateKey) error {
+	return buildOnConflict(bu, odk, "excluded.")
+}
+
+// buildSetPart SQLite 不支持给集合操作的查询加括号，只能改写成子查询
+func (d *sqliteDialect) buildSetPart(b *builder, part string) {
+	b.sb.WriteString("SELECT * FROM (")
+	b.sb.WriteString(part)
+	b.sb.WriteByte(')')
+}
+
+// buildLock SQLite 锁的是整个数据库，不支持行锁
+func (d *sqliteDialect) buildLock(b *builder, mode lockMode, wait lockWait) error {
+	if mode == lockModeShare {
+		return err2.NewErrUnsupportedClause("FOR SHARE")
+	}
+	return err2.NewErrUnsupportedClause("FOR UPDATE")
+}
+
+// buildReturning SQLite 3.35 之后支持 RETURNING
+func (d *sqliteDialect) buildReturning(bu *builder, fields []*model.Field) error {
+	return buildReturning(bu, fields)
+}
+
+// buildOnConflict 构造 ON CONFLICT (...) DO UPDATE SET
+// Postgres 和 SQLite 的语法一致，只是引用新值的写法不同
+func buildOnConflict(bu *builder, odk *OnDuplicateKey, excluded string) error {
+	if len(odk.conflictColumns) == 0 {
+		return err2.ErrMissingConflictColumns
+	}
+	bu.sb.WriteString(" ON CONFLICT (")
+	for i, col := range odk.conflictColumns {
+		if i > 0 {
+			bu.sb.WriteByte(',')
+		}
+		if err := bu.buildColumn(col); err != nil {
+			return err
+		}
+	}
+	bu.sb.WriteString(") DO UPDATE SET ")
+
+	for i, assign := range odk.assigns {
+		if i > 0 {
+			bu.sb.WriteByte(',')
+		}
+		switch a := assign.(type) {
+		case Assignment:
+			if err := bu.buildColumn(a.column); err != nil {
+				return err
+			}
+			bu.sb.WriteByte('=')
+			if err := bu.buildAssignValue(a.val); err != nil {
+				return err
+			}
+		case Column:
+			fd, ok := bu.m.FieldMap[a.name]
+			if !ok {
+				return err2.NewErrUnknownColumn(a.name)
+			}
+			bu.quote(fd.ColName)
+			bu.sb.WriteByte('=')
+			bu.sb.WriteString(excluded)
+			bu.quote(fd.ColName)
+		default:
+			return err2.NewErrUnsupportedAssignableType(assign)
+		}
+	}
+	return nil
+}
+
+func buildReturning(bu *builder, fields []*model.Field) error {
+	bu.sb.WriteString(" RETURNING ")
+	for i, fd := range fields {
+		if i > 0 {
+			bu.sb.WriteByte(',')
+		}
+		bu.quote(fd.ColName)
+	}
+	return nil
+}
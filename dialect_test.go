@@ -0,0 +1,177 @@
+package go_orm
+
+import (
+	"context"
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	err2 "go-orm/internal/err"
+	"testing"
+)
+
+func TestPostgresDialect_Build(t *testing.T) {
+	db := memoryDB(DBWithDialect(Postgres))
+	tests := []struct {
+		name    string
+		b       QueryBuilder
+		want    *Query
+		wantErr error
+	}{
+		{
+			name: "insert",
+			b: NewInserter[TestModel](db).Columns("FirstName", "Age").
+				Values(&TestModel{FirstName: "liu", Age: 18}, &TestModel{FirstName: "wang", Age: 30}),
+			want: &Query{
+				SQL:  `INSERT INTO "test_model"("first_name","age")VALUES($1,$2),($3,$4);`,
+				Args: []any{"liu", int8(18), "wang", int8(30)},
+			},
+		},
+		{
+			name: "upsert",
+			b: NewInserter[TestModel](db).Columns("Id", "FirstName", "Age").
+				Values(&TestModel{Id: 1, FirstName: "liu", Age: 18}).
+				OnDuplicateKey().ConflictColumns("Id").Update(C("FirstName"), Assign("Age", 19)),
+			want: &Query{
+				SQL: `INSERT INTO "test_model"("id","first_name","age")VALUES($1,$2,$3)` +
+					` ON CONFLICT ("id") DO UPDATE SET "first_name"=EXCLUDED."first_name","age"=$4;`,
+				Args: []any{int64(1), "liu", int8(18), 19},
+			},
+		},
+		{
+			name: "upsert without conflict columns",
+			b: NewInserter[TestModel](db).Values(&TestModel{Id: 1}).
+				OnDuplicateKey().Update(C("FirstName")),
+			wantErr: ErrMissingConflictColumns,
+		},
+		{
+			name: "returning",
+			b: NewInserter[TestModel](db).Columns("FirstName").
+				Values(&TestModel{FirstName: "liu"}).Returning("Id", "Age"),
+			want: &Query{
+				SQL:  `INSERT INTO "test_model"("first_name")VALUES($1) RETURNING "id","age";`,
+				Args: []any{"liu"},
+			},
+		},
+		{
+			name: "update",
+			b:    NewUpdater[TestModel](db).Set(Assign("Age", 19)).Where(C("Id").EQ(1), C("Age").LT(18)),
+			want: &Query{
+				SQL:  `UPDATE "test_model" SET "age"=$1 WHERE ("id" = $2) AND ("age" < $3);`,
+				Args: []any{19, 1, 18},
+			},
+		},
+		{
+			name: "select",
+			b: NewSelector[TestModel](db).Select(C("Id"), Avg("Age").As("avg_age")).
+				From("test_db.test_model").Where(C("FirstName").EQ("liu"), Not(C("Age").GT(18))).
+				GroupBy(C("Id")).Having(C("Id").LT(10)).OrderBy(Desc("Id")).Limit(10).Offset(20),
+			want: &Query{
+				SQL: `SELECT "id",AVG("age") as "avg_age" FROM "test_db"."test_model"` +
+					` WHERE ("first_name" = $1) AND (NOT ("age" > $2)) GROUP BY "id" HAVING "id" < $3` +
+					` ORDER BY "id" DESC LIMIT $4 OFFSET $5;`,
+				Args: []any{"liu", 18, 10, int32(10), int32(20)},
+			},
+		},
+		{
+			name: "for update nowait",
+			b:    NewSelector[TestModel](db).Where(C("Id").EQ(1)).Limit(1).ForUpdate().NoWait(),
+			want: &Query{
+				SQL:  `SELECT * FROM "test_model" WHERE "id" = $1 LIMIT $2 FOR UPDATE NOWAIT;`,
+				Args: []any{1, int32(1)},
+			},
+		},
+		{
+			name: "for share skip locked",
+			b:    NewSelector[TestModel](db).ForShare().SkipLocked(),
+			want: &Query{
+				SQL: `SELECT * FROM "test_model" FOR SHARE SKIP LOCKED;`,
+			},
+		},
+		{
+			name: "raw placeholders",
+			b: NewSelector[TestModel](db).Select(Raw(`COALESCE("last_name", ?)`, "none")).
+				Where(C("Id").GT(1), Raw(`"age" > ? AND "first_name" != '?'`, 18).AsPredicate()).
+				OrderBy(Raw(`"age" + ?`, 2).Asc()),
+			want: &Query{
+				SQL: `SELECT COALESCE("last_name", $1) FROM "test_model" WHERE ("id" > $2) AND ("age" > $3 AND "first_name" != '?')` +
+					` ORDER BY "age" + $4 ASC;`,
+				Args: []any{"none", 1, 18, 2},
+			},
+		},
+		{
+			name:    "raw args mismatch",
+			b:       NewSelector[TestModel](db).Where(Raw(`"age" > ? OR "age" < ?`, 1).AsPredicate(), C("Id").EQ(7)),
+			wantErr: err2.NewErrRawArgsMismatch(`"age" > ? OR "age" < ?`, 2, 1),
+		},
+		{
+			name: "order by nulls",
+			b:    NewSelector[TestModel](db).OrderBy(Desc("LastName").NullsLast(), C("Age").Asc().NullsFirst()),
+			want: &Query{
+				SQL: `SELECT * FROM "test_model" ORDER BY "last_name" DESC NULLS LAST,"age" ASC NULLS FIRST;`,
+			},
+		},
+		{
+			name: "subquery placeholders",
+			b: NewSelector[TestModel](db).Where(C("Age").GT(18),
+				C("Id").In(NewSelector[TestModel](db).Select(C("Id")).Where(C("FirstName").EQ("liu")).Limit(5))).Limit(1),
+			want: &Query{
+				SQL: `SELECT * FROM "test_model" WHERE ("age" > $1) AND ("id" IN (SELECT "id" FROM "test_model" WHERE "first_name" = $2 LIMIT $3))` +
+					` LIMIT $4;`,
+				Args: []any{18, "liu", int32(5), int32(1)},
+			},
+		},
+		{
+			name:    "delete limit",
+			b:       NewDeleter[TestModel](db).Where(C("Id").EQ(1)).Limit(1),
+			wantErr: err2.NewErrUnsupportedClause("DELETE ... LIMIT"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.b.Build()
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMySQLDialect_Returning(t *testing.T) {
+	_, err := NewInserter[TestModel](memoryDB()).Values(&TestModel{}).Returning("Id").Build()
+	assert.Equal(t, err2.NewErrUnsupportedClause("RETURNING"), err)
+}
+
+func TestInserter_Returning(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+
+	db, err := OpenDB(mockDB, DBWithDialect(Postgres))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "last_name"})
+	rows.AddRow([]byte("1"), []byte("quan"))
+	rows.AddRow([]byte("2"), nil)
+	mock.ExpectQuery(`INSERT INTO "test_model"\("first_name"\)VALUES\(\$1\),\(\$2\) RETURNING "id","last_name";`).
+		WithArgs("liu", "wang").
+		WillReturnRows(rows)
+
+	vals := []*TestModel{{FirstName: "liu"}, {FirstName: "wang"}}
+	res := NewInserter[TestModel](db).Columns("FirstName").Values(vals...).
+		Returning("Id", "LastName").Exec(context.Background())
+	affected, err := res.RowsAffected()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, int64(2), affected)
+	assert.Equal(t, []*TestModel{
+		{Id: 1, FirstName: "liu", LastName: &sql.NullString{String: "quan", Valid: true}},
+		{Id: 2, FirstName: "wang"},
+	}, vals)
+}
@@ -3,5 +3,18 @@ package go_orm
 import "go-orm/internal/err"
 
 var (
-	ErrNoRows = err.ErrNoRows
+	ErrNoRows                 = err.ErrNoRows
+	ErrNoUpdatedColumns       = err.ErrNoUpdatedColumns
+	ErrColumnWithoutEntity    = err.ErrColumnWithoutEntity
+	ErrDeleteWithoutWhere     = err.ErrDeleteWithoutWhere
+	ErrMissingConflictColumns = err.ErrMissingConflictColumns
+	ErrInvalidCursor          = err.ErrInvalidCursor
+	ErrCursorWithoutOrderBy   = err.ErrCursorWithoutOrderBy
+	ErrCursorKeyNotSelected   = err.ErrCursorKeyNotSelected
+	ErrInvalidPageSize        = err.ErrInvalidPageSize
+	ErrInvalidPage            = err.ErrInvalidPage
+	ErrPageOutOfRange         = err.ErrPageOutOfRange
+	ErrLockOptionWithoutLock  = err.ErrLockOptionWithoutLock
+	ErrEmptyPredicate         = err.ErrEmptyPredicate
+	ErrSubqueryWithoutAlias   = err.ErrSubqueryWithoutAlias
 )
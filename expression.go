@@ -6,6 +6,9 @@ type Expression interface {
 	expr()
 }
 
+// RawExpr 原生 SQL 片段
+// 可以用在 SELECT、WHERE、HAVING、ORDER BY 以及 Assign 的值里
+// 参数使用 ? 作为占位符，输出的时候会按照方言转换，例如 Postgres 的 $1
 type RawExpr struct {
 	raw  string
 	args []any
@@ -13,6 +16,8 @@ type RawExpr struct {
 
 func (r RawExpr) selectable() {}
 
+func (r RawExpr) expr() {}
+
 func Raw(raw string, args ...any) RawExpr {
 	return RawExpr{
 		raw:  raw,
@@ -21,5 +26,186 @@ func Raw(raw string, args ...any) RawExpr {
 }
 
 func (r RawExpr) AsPredicate() Predicate {
-	return Predicate{}
+	return Predicate{
+		left: r,
+	}
+}
+
+func (r RawExpr) Asc() OrderBy {
+	return OrderBy{
+		expr:  r,
+		order: "ASC",
+	}
+}
+
+func (r RawExpr) Desc() OrderBy {
+	return OrderBy{
+		expr:  r,
+		order: "DESC",
+	}
+}
+
+// valueOf 表达式原样使用，其它的值作为参数
+// 这样 C("Price").Multi(C("Qty")) 和 C("Age").Add(1) 都可以表达
+func valueOf(val any) Expression {
+	switch v := val.(type) {
+	case Expression:
+		return v
+	default:
+		return Value{val: v}
+	}
+}
+
+// MathExpr 算术表达式，例如 `age` + ?
+type MathExpr struct {
+	left  Expression
+	op    op
+	right Expression
+	alias string
+}
+
+func (m MathExpr) expr() {}
+
+func (m MathExpr) selectable() {}
+
+func (m MathExpr) As(alias string) MathExpr {
+	m.alias = alias
+	return m
+}
+
+func (m MathExpr) Add(val any) MathExpr {
+	return MathExpr{
+		left:  m,
+		op:    opAdd,
+		right: valueOf(val),
+	}
+}
+
+func (m MathExpr) Sub(val any) MathExpr {
+	return MathExpr{
+		left:  m,
+		op:    opSub,
+		right: valueOf(val),
+	}
+}
+
+func (m MathExpr) Multi(val any) MathExpr {
+	return MathExpr{
+		left:  m,
+		op:    opMulti,
+		right: valueOf(val),
+	}
+}
+
+func (m MathExpr) Div(val any) MathExpr {
+	return MathExpr{
+		left:  m,
+		op:    opDiv,
+		right: valueOf(val),
+	}
+}
+
+func (m MathExpr) Asc() OrderBy {
+	return OrderBy{
+		expr:  m,
+		order: "ASC",
+	}
+}
+
+func (m MathExpr) Desc() OrderBy {
+	return OrderBy{
+		expr:  m,
+		order: "DESC",
+	}
+}
+
+func (m MathExpr) EQ(val any) Predicate {
+	return newPredicate(m, opEQ, val)
+}
+
+func (m MathExpr) NEQ(val any) Predicate {
+	return newPredicate(m, opNEQ, val)
+}
+
+func (m MathExpr) GT(val any) Predicate {
+	return newPredicate(m, opGT, val)
+}
+
+func (m MathExpr) GTE(val any) Predicate {
+	return newPredicate(m, opGTE, val)
+}
+
+func (m MathExpr) LT(val any) Predicate {
+	return newPredicate(m, opLT, val)
+}
+
+func (m MathExpr) LTE(val any) Predicate {
+	return newPredicate(m, opLTE, val)
+}
+
+// FuncExpr SQL 函数调用，例如 LOWER(`name`)
+type FuncExpr struct {
+	name  string
+	args  []Expression
+	alias string
+}
+
+// Fn 构造函数调用，参数可以是列、表达式或者普通的值
+// 例如 Fn("COALESCE", C("LastName"), "")
+func Fn(name string, args ...any) FuncExpr {
+	exprs := make([]Expression, 0, len(args))
+	for _, arg := range args {
+		exprs = append(exprs, valueOf(arg))
+	}
+	return FuncExpr{
+		name: name,
+		args: exprs,
+	}
+}
+
+func (f FuncExpr) expr() {}
+
+func (f FuncExpr) selectable() {}
+
+func (f FuncExpr) As(alias string) FuncExpr {
+	f.alias = alias
+	return f
+}
+
+func (f FuncExpr) Asc() OrderBy {
+	return OrderBy{
+		expr:  f,
+		order: "ASC",
+	}
+}
+
+func (f FuncExpr) Desc() OrderBy {
+	return OrderBy{
+		expr:  f,
+		order: "DESC",
+	}
+}
+
+func (f FuncExpr) EQ(val any) Predicate {
+	return newPredicate(f, opEQ, val)
+}
+
+func (f FuncExpr) NEQ(val any) Predicate {
+	return newPredicate(f, opNEQ, val)
+}
+
+func (f FuncExpr) GT(val any) Predicate {
+	return newPredicate(f, opGT, val)
+}
+
+func (f FuncExpr) GTE(val any) Predicate {
+	return newPredicate(f, opGTE, val)
+}
+
+func (f FuncExpr) LT(val any) Predicate {
+	return newPredicate(f, opLT, val)
+}
+
+func (f FuncExpr) LTE(val any) Predicate {
+	return newPredicate(f, opLTE, val)
 }
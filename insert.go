@@ -3,6 +3,7 @@ package go_orm
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	err2 "go-orm/internal/err"
 	"go-orm/internal/model"
@@ -11,25 +12,54 @@ import (
 
 type Inserter[T any] struct {
 	builder
-	core
 	sess Session
 	vals []*T
 	cols []string
+	// returning 需要数据库回传的列
+	returning []string
+	mode      insertMode
 
 	onDuplicate *OnDuplicateKey
 }
 
+// Exec 执行插入
+// 指定了 Returning 的时候，回传的列会按照顺序写回 Values 中的实体
 func (i *Inserter[T]) Exec(ctx context.Context) sql.Result {
-	build, err := i.Build()
-	if err != nil {
+	if len(i.returning) == 0 {
+		return exec(ctx, i.sess, i.core, &QueryContext{
+			Type:    "INSERT",
+			Builder: i,
+		})
+	}
+
+	res := query(ctx, i.sess, i.core, &QueryContext{
+		Type:    "INSERT",
+		Builder: i,
+	}, func(rows *sql.Rows) (any, error) {
+		defer rows.Close()
+		var cnt int64
+		for rows.Next() && int(cnt) < len(i.vals) {
+			if err := i.valCreator(i.vals[cnt], i.m).SetColumns(rows); err != nil {
+				return nil, err
+			}
+			cnt++
+		}
+		return driver.RowsAffected(cnt), rows.Err()
+	})
+	if res.Err != nil {
+		return Result{
+			err: res.Err,
+		}
+	}
+
+	r, ok := res.Result.(sql.Result)
+	if !ok {
 		return Result{
-			err: err,
+			err: errors.New("类型错误"),
 		}
 	}
-	exec, err := i.sess.exec(build.SQL, build.Args...)
 	return Result{
-		res: exec,
-		err: err,
+		res: r,
 	}
 }
 
@@ -37,9 +67,8 @@ func NewInserter[T any](sess Session) *Inserter[T] {
 	c := sess.getCore()
 	return &Inserter[T]{
 		builder: builder{
-			dialect: c.dialect,
+			core: c,
 		},
-		core: c,
 		sess: sess,
 	}
 }
@@ -54,12 +83,39 @@ func (i *Inserter[T]) Columns(vals ...string) *Inserter[T] {
 	return i
 }
 
+// Ignore 冲突的时候忽略该行
+// MySQL 对应 INSERT IGNORE，SQLite 对应 INSERT OR IGNORE
+func (i *Inserter[T]) Ignore() *Inserter[T] {
+	i.mode = insertModeIgnore
+	return i
+}
+
+// Replace 冲突的时候替换旧行
+// MySQL 对应 REPLACE INTO，SQLite 对应 INSERT OR REPLACE
+func (i *Inserter[T]) Replace() *Inserter[T] {
+	i.mode = insertModeReplace
+	return i
+}
+
+// Returning 指定插入之后需要回传的列，例如自增主键和默认值
+// 只有支持 RETURNING 的方言才能使用，例如 Postgres
+func (i *Inserter[T]) Returning(cols ...string) *Inserter[T] {
+	i.returning = cols
+	return i
+}
+
 func (i *Inserter[T]) Build() (*Query, error) {
 	if len(i.vals) <= 0 {
 		return nil, errors.New("err")
 	}
 
-	i.sb.WriteString("INSERT INTO ")
+	insertInto, err := i.core.dialect.insertInto(i.mode)
+	if err != nil {
+		return nil, err
+	}
+
+	i.reset()
+	i.sb.WriteString(insertInto)
 
 	m, err := i.r.Get(i.vals[0])
 	if err != nil {
@@ -101,8 +157,7 @@ func (i *Inserter[T]) Build() (*Query, error) {
 			if i2 > 0 {
 				i.sb.WriteByte(',')
 			}
-			i.sb.WriteByte('?')
-			i.args = append(i.args, of.FieldByIndex(c.Index).Interface())
+			i.parameter(of.FieldByIndex(c.Index).Interface())
 		}
 	}
 	i.sb.WriteString(")")
@@ -115,6 +170,20 @@ func (i *Inserter[T]) Build() (*Query, error) {
 		}
 	}
 
+	if len(i.returning) > 0 {
+		fds := make([]*model.Field, 0, len(i.returning))
+		for _, col := range i.returning {
+			fd, ok := m.FieldMap[col]
+			if !ok {
+				return nil, err2.NewErrUnknownColumn(col)
+			}
+			fds = append(fds, fd)
+		}
+		if err = i.core.dialect.buildReturning(&i.builder, fds); err != nil {
+			return nil, err
+		}
+	}
+
 	i.sb.WriteString(";")
 
 	return &Query{
@@ -130,16 +199,26 @@ func (i *Inserter[T]) OnDuplicateKey() *OnDuplicateKeyBuilder[T] {
 }
 
 type OnDuplicateKeyBuilder[T any] struct {
-	i *Inserter[T]
+	i               *Inserter[T]
+	conflictColumns []string
+}
+
+// ConflictColumns 指定冲突列
+// MySQL 会忽略它，Postgres 的 ON CONFLICT 必须指定
+func (o *OnDuplicateKeyBuilder[T]) ConflictColumns(cols ...string) *OnDuplicateKeyBuilder[T] {
+	o.conflictColumns = cols
+	return o
 }
 
-func (o *OnDuplicateKeyBuilder[T]) update(assign ...Assignable) *Inserter[T] {
+func (o *OnDuplicateKeyBuilder[T]) Update(assign ...Assignable) *Inserter[T] {
 	o.i.onDuplicate = &OnDuplicateKey{
-		assigns: assign,
+		assigns:         assign,
+		conflictColumns: o.conflictColumns,
 	}
 	return o.i
 }
 
 type OnDuplicateKey struct {
-	assigns []Assignable
+	assigns         []Assignable
+	conflictColumns []string
 }
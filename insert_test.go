@@ -77,7 +77,7 @@ func TestInserter_Build(t *testing.T) {
 				FirstName: "liu",
 				Age:       28,
 				LastName:  &sql.NullString{Valid: true, String: "quan"},
-			}).OnDuplicateKey().update(Assign("Age", 19)),
+			}).OnDuplicateKey().Update(Assign("Age", 19)),
 			want: &Query{
 				SQL: "INSERT INTO `test_model`(`id`,`first_name`,`age`,`last_name`)VALUES(?,?,?,?)" +
 					" ON DUPLICATE KEY UPDATE `age`=?;",
@@ -85,6 +85,30 @@ func TestInserter_Build(t *testing.T) {
 			},
 			wantErr: nil,
 		},
+		{
+			name: "upsert raw",
+			i: NewInserter[TestModel](memoryDB()).Columns("Id", "Age").Values(&TestModel{
+				Id:  12,
+				Age: 28,
+			}).OnDuplicateKey().Update(Assign("Age", Raw("`age` + ?", 1))),
+			want: &Query{
+				SQL:  "INSERT INTO `test_model`(`id`,`age`)VALUES(?,?) ON DUPLICATE KEY UPDATE `age`=`age` + ?;",
+				Args: []any{int64(12), int8(28), 1},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "upsert math",
+			i: NewInserter[TestModel](memoryDB()).Columns("Id", "Age").Values(&TestModel{
+				Id:  12,
+				Age: 28,
+			}).OnDuplicateKey().Update(Assign("Age", C("Age").Add(1))),
+			want: &Query{
+				SQL:  "INSERT INTO `test_model`(`id`,`age`)VALUES(?,?) ON DUPLICATE KEY UPDATE `age`=`age` + ?;",
+				Args: []any{int64(12), int8(28), 1},
+			},
+			wantErr: nil,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -15,6 +15,34 @@ var (
 	ErrTooManyReturnedColumns = errors.New("orm: 过多列")
 	// ErrInsertZeroRow 代表插入 0 行
 	ErrInsertZeroRow = errors.New("orm: 插入 0 行")
+	// ErrNoUpdatedColumns 代表 UPDATE 语句既没有指定 SET 的列，也没有提供实体
+	ErrNoUpdatedColumns = errors.New("orm: 未指定需要更新的列")
+	// ErrColumnWithoutEntity 代表 Set 里面用 Column 指定了列，但是没有通过 Update 提供实体
+	// 这时候不知道列的值，需要提供实体或者改用 Assign
+	ErrColumnWithoutEntity = errors.New("orm: 使用 Column 更新的时候必须提供实体")
+	// ErrDeleteWithoutWhere 代表 DELETE 语句没有 WHERE 条件
+	// 需要删除全表的时候，要显式调用 AllowFullTable
+	ErrDeleteWithoutWhere = errors.New("orm: DELETE 语句缺少 WHERE 条件")
+	// ErrMissingConflictColumns 代表 ON CONFLICT 没有指定冲突列
+	ErrMissingConflictColumns = errors.New("orm: ON CONFLICT 未指定冲突列")
+	// ErrInvalidCursor 代表游标无法解析，或者和当前的 ORDER BY 不匹配
+	ErrInvalidCursor = errors.New("orm: 无效的游标")
+	// ErrCursorWithoutOrderBy 代表游标分页没有指定 ORDER BY
+	ErrCursorWithoutOrderBy = errors.New("orm: 游标分页必须指定 ORDER BY")
+	// ErrCursorKeyNotSelected 代表游标分页排序的列没有出现在 SELECT 里面，无法从结果中取到游标的值
+	ErrCursorKeyNotSelected = errors.New("orm: 游标分页排序的列必须出现在 SELECT 里面")
+	// ErrInvalidPageSize 代表分页的大小小于等于 0
+	ErrInvalidPageSize = errors.New("orm: 分页大小必须大于 0")
+	// ErrInvalidPage 代表页码小于等于 0，页码从 1 开始
+	ErrInvalidPage = errors.New("orm: 页码必须大于 0")
+	// ErrPageOutOfRange 代表分页的偏移量超出了 OFFSET 能够表示的范围
+	ErrPageOutOfRange = errors.New("orm: 分页的偏移量超出范围")
+	// ErrSubqueryWithoutAlias 代表在 FROM 或者 JOIN 里面使用了没有别名的子查询
+	ErrSubqueryWithoutAlias = errors.New("orm: 作为表使用的子查询必须指定别名")
+	// ErrEmptyPredicate 代表使用了零值的 Predicate
+	ErrEmptyPredicate = errors.New("orm: 空的查询条件")
+	// ErrLockOptionWithoutLock 代表使用了 NoWait 或者 SkipLocked，但是没有使用 ForUpdate 或者 ForShare
+	ErrLockOptionWithoutLock = errors.New("orm: NOWAIT 和 SKIP LOCKED 必须和 FOR UPDATE 或者 FOR SHARE 一起使用")
 )
 
 // NewErrUnknownField 返回代表未知字段的错误
@@ -46,6 +74,11 @@ func NewErrUnsupportedSelectable(exp any) error {
 	return fmt.Errorf("orm: 不支持的目标列 %v", exp)
 }
 
+// NewErrUnsupportedTable 返回一个不支持该 TableReference 的错误信息
+func NewErrUnsupportedTable(table any) error {
+	return fmt.Errorf("orm: 不支持的表 %v", table)
+}
+
 // 后面可以考虑支持错误码
 // func NewErrUnsupportedExpressionType(exp any) error {
 // 	return fmt.Errorf("orm-50001: 不支持的表达式 %v", exp)
@@ -56,6 +89,16 @@ func NewErrUnsupportedSelectable(exp any) error {
 // 发生该错误，主要是因为传入了不支持的 Expression 的实际类型
 // 一般来说，这是因为中间件
 
+// NewErrRawArgsMismatch 返回原生 SQL 的占位符个数和参数个数不一致的错误信息
+func NewErrRawArgsMismatch(raw string, placeholders, args int) error {
+	return fmt.Errorf("orm: 原生 SQL %s 有 %d 个占位符，但是提供了 %d 个参数", raw, placeholders, args)
+}
+
+// NewErrUnsupportedClause 返回当前方言不支持该语法的错误信息
+func NewErrUnsupportedClause(clause string) error {
+	return fmt.Errorf("orm: 当前方言不支持 %s", clause)
+}
+
 func NewErrInvalidTagContent(tag string) error {
 	return fmt.Errorf("orm: 错误的标签设置: %s", tag)
 }
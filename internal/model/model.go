@@ -45,6 +45,10 @@ type Field struct {
 	Offset uintptr
 
 	Index []int
+
+	// PrimaryKey 是否是主键，通过标签 orm:"primary_key" 指定
+	// 没有任何字段指定的时候，Id 字段作为主键
+	PrimaryKey bool
 }
 
 type TableName interface {
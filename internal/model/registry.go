@@ -57,11 +57,13 @@ func (r *Registrys) parseModel(val any) (*Model, error) {
 	columns := make([]*Field, fieldCnt)
 	fieldMap := make(map[string]*Field)
 	columnMap := make(map[string]*Field)
+	hasPK := false
 	for i := 0; i < fieldCnt; i++ {
 		fd := of.Field(i)
 
 		var colName string
 		var ok bool
+		var pk bool
 		if fd.Tag == "" {
 			colName = underscoreName(fd.Name)
 		} else {
@@ -73,14 +75,17 @@ func (r *Registrys) parseModel(val any) (*Model, error) {
 			if !ok || colName == "" {
 				colName = underscoreName(fd.Name)
 			}
+			_, pk = tags["primary_key"]
+			hasPK = hasPK || pk
 		}
 
 		fieldV := &Field{
-			GoName:  fd.Name,
-			ColName: colName,
-			Typ:     fd.Type,
-			Offset:  fd.Offset,
-			Index:   fd.Index,
+			GoName:     fd.Name,
+			ColName:    colName,
+			Typ:        fd.Type,
+			Offset:     fd.Offset,
+			Index:      fd.Index,
+			PrimaryKey: pk,
 		}
 
 		fieldMap[fd.Name] = fieldV
@@ -88,6 +93,12 @@ func (r *Registrys) parseModel(val any) (*Model, error) {
 		columns[i] = fieldV
 	}
 
+	if !hasPK {
+		if fd, ok := fieldMap["Id"]; ok {
+			fd.PrimaryKey = true
+		}
+	}
+
 	var tableName string
 	if tn, ok := val.(TableName); ok {
 		tableName = tn.TableName()
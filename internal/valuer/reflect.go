@@ -5,40 +5,32 @@ import (
 	err2 "go-orm/internal/err"
 	go_orm "go-orm/internal/model"
 	"reflect"
-	"unsafe"
 )
 
 type ReflectValue struct {
 	t     any
 	model *go_orm.Model
-	addr  unsafe.Pointer
+	val   reflect.Value
 }
 
 func NewReflectValue(t any, model *go_orm.Model) Value {
-	addr := unsafe.Pointer(reflect.ValueOf(t).Pointer())
 	return &ReflectValue{
 		t:     t,
 		model: model,
-		addr:  addr,
+		val:   reflect.ValueOf(t).Elem(),
 	}
 }
 
 func (r *ReflectValue) Field(name string) (any, error) {
 	fdMeta, ok := r.model.FieldMap[name]
 	if !ok {
-		return 0, err2.NewErrUnknownColumn(name)
+		return nil, err2.NewErrUnknownField(name)
 	}
 
-	ptr := unsafe.Pointer(uintptr(r.addr) + fdMeta.Offset)
-	at := reflect.NewAt(fdMeta.Typ, ptr)
-	return at, nil
+	return r.val.FieldByIndex(fdMeta.Index).Interface(), nil
 }
 
 func (r *ReflectValue) SetColumns(rows *sql.Rows) error {
-	if !rows.Next() {
-		return err2.ErrNoRows
-	}
-
 	columns, err := rows.Columns()
 	if err != nil {
 		return err
@@ -51,7 +43,10 @@ func (r *ReflectValue) SetColumns(rows *sql.Rows) error {
 	vals := make([]any, 0, len(columns))
 	eleVals := make([]reflect.Value, 0, len(columns))
 	for _, column := range columns {
-		f := r.model.ColumnMap[column]
+		f, ok := r.model.ColumnMap[column]
+		if !ok {
+			return err2.NewErrUnknownColumn(column)
+		}
 
 		fdVal := reflect.New(f.Typ)
 		eleVals = append(eleVals, fdVal.Elem())
@@ -64,14 +59,9 @@ func (r *ReflectValue) SetColumns(rows *sql.Rows) error {
 		return err
 	}
 
-	t := r.t
-	tVal := reflect.ValueOf(t).Elem()
 	for i, column := range columns {
-		f, ok := r.model.ColumnMap[column]
-		if !ok {
-			return err2.NewErrUnknownColumn(column)
-		}
-		tVal.FieldByName(f.GoName).Set(eleVals[i])
+		f := r.model.ColumnMap[column]
+		r.val.FieldByIndex(f.Index).Set(eleVals[i])
 	}
 	return nil
 }
@@ -7,6 +7,8 @@ import (
 
 // Value reflect 和 unsafe 的抽象
 type Value interface {
+	// SetColumns 将当前行的数据写入到结构体中
+	// 调用方需要先调用 rows.Next()
 	SetColumns(row *sql.Rows) error
 
 	Field(name string) (any, error)
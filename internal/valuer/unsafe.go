@@ -8,27 +8,35 @@ import (
 	"unsafe"
 )
 
+// UnsafeValue 基于字段偏移量直接读写内存
+// 字段地址 = 结构体起始地址 + 字段偏移量
 type UnsafeValue struct {
 	t     any
 	model *model.Model
-	addr  unsafe.Pointer
+	// 结构体的起始地址
+	addr unsafe.Pointer
 }
 
 func NewUnsafeValue(t any, model *model.Model) Value {
-	//addr := unsafe.Pointer(reflect.ValueOf(t).Pointer())
-	return nil
-	//return &UnsafeValue{
-	//	t:     t,
-	//	model: model,
-	//	addr:  addr,
-	//}
+	addr := unsafe.Pointer(reflect.ValueOf(t).Pointer())
+	return &UnsafeValue{
+		t:     t,
+		model: model,
+		addr:  addr,
+	}
 }
 
-func (u *UnsafeValue) SetColumns(rows *sql.Rows) error {
-	if !rows.Next() {
-		return err2.ErrNoRows
+func (u *UnsafeValue) Field(name string) (any, error) {
+	fd, ok := u.model.FieldMap[name]
+	if !ok {
+		return nil, err2.NewErrUnknownField(name)
 	}
 
+	ptr := unsafe.Pointer(uintptr(u.addr) + fd.Offset)
+	return reflect.NewAt(fd.Typ, ptr).Elem().Interface(), nil
+}
+
+func (u *UnsafeValue) SetColumns(rows *sql.Rows) error {
 	columns, err := rows.Columns()
 	if err != nil {
 		return err
@@ -40,7 +48,10 @@ func (u *UnsafeValue) SetColumns(rows *sql.Rows) error {
 
 	vals := make([]any, 0, len(columns))
 	for _, column := range columns {
-		f := u.model.ColumnMap[column]
+		f, ok := u.model.ColumnMap[column]
+		if !ok {
+			return err2.NewErrUnknownColumn(column)
+		}
 
 		fdVal := reflect.NewAt(f.Typ, unsafe.Pointer(uintptr(u.addr)+f.Offset))
 		vals = append(vals, fdVal.Interface())
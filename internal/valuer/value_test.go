@@ -0,0 +1,280 @@
+package valuer
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	err2 "go-orm/internal/err"
+	"go-orm/internal/model"
+	"reflect"
+	"testing"
+)
+
+type SimpleStruct struct {
+	Id        int64
+	FirstName string
+	Age       int8
+	LastName  *sql.NullString
+}
+
+// WideStruct 用于对比 unsafe 和 reflect 在宽表上的性能
+type WideStruct struct {
+	Col1  int64
+	Col2  string
+	Col3  int32
+	Col4  string
+	Col5  float64
+	Col6  string
+	Col7  int64
+	Col8  string
+	Col9  int32
+	Col10 string
+	Col11 float64
+	Col12 string
+	Col13 int64
+	Col14 string
+	Col15 int32
+	Col16 string
+	Col17 float64
+	Col18 string
+	Col19 int64
+	Col20 string
+	Col21 int32
+	Col22 string
+	Col23 float64
+	Col24 string
+	Col25 int64
+	Col26 string
+	Col27 int32
+	Col28 string
+	Col29 float64
+	Col30 string
+}
+
+func newRegistry() *model.Registrys {
+	return &model.Registrys{
+		Models: map[reflect.Type]*model.Model{},
+	}
+}
+
+func TestValue_SetColumns(t *testing.T) {
+	testSetColumns(t, NewReflectValue)
+	testSetColumns(t, NewUnsafeValue)
+}
+
+func testSetColumns(t *testing.T, creator Creator) {
+	tests := []struct {
+		name    string
+		cs      map[string][]byte
+		want    *SimpleStruct
+		wantErr error
+	}{
+		{
+			name: "all columns",
+			cs: map[string][]byte{
+				"id":         []byte("1"),
+				"first_name": []byte("Liu"),
+				"age":        []byte("18"),
+				"last_name":  []byte("Quan"),
+			},
+			want: &SimpleStruct{
+				Id:        1,
+				FirstName: "Liu",
+				Age:       18,
+				LastName:  &sql.NullString{String: "Quan", Valid: true},
+			},
+		},
+		{
+			name: "partial columns",
+			cs: map[string][]byte{
+				"id":  []byte("1"),
+				"age": []byte("18"),
+			},
+			want: &SimpleStruct{
+				Id:  1,
+				Age: 18,
+			},
+		},
+		{
+			name: "unknown column",
+			cs: map[string][]byte{
+				"invalid": []byte("1"),
+			},
+			wantErr: err2.NewErrUnknownColumn("invalid"),
+		},
+	}
+
+	r := newRegistry()
+	m, err := r.Get(&SimpleStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
+
+			// map 无序，按照模型的列顺序来构造
+			cols := make([]string, 0, len(tt.cs))
+			vals := make([]driver.Value, 0, len(tt.cs))
+			for _, fd := range m.Columns {
+				if v, ok := tt.cs[fd.ColName]; ok {
+					cols = append(cols, fd.ColName)
+					vals = append(vals, v)
+				}
+			}
+			if v, ok := tt.cs["invalid"]; ok {
+				cols = append(cols, "invalid")
+				vals = append(vals, v)
+			}
+			mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(cols).AddRow(vals...))
+
+			rows, err := db.Query("SELECT xxx")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rows.Close()
+			assert.True(t, rows.Next())
+
+			s := &SimpleStruct{}
+			err = creator(s, m).SetColumns(rows)
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.want, s)
+		})
+	}
+}
+
+func TestValue_Field(t *testing.T) {
+	testField(t, NewReflectValue)
+	testField(t, NewUnsafeValue)
+}
+
+func testField(t *testing.T, creator Creator) {
+	r := newRegistry()
+	m, err := r.Get(&SimpleStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &SimpleStruct{
+		Id:        1,
+		FirstName: "Liu",
+		Age:       18,
+		LastName:  &sql.NullString{String: "Quan", Valid: true},
+	}
+	val := creator(s, m)
+
+	tests := []struct {
+		name    string
+		field   string
+		want    any
+		wantErr error
+	}{
+		{name: "int64", field: "Id", want: int64(1)},
+		{name: "string", field: "FirstName", want: "Liu"},
+		{name: "int8", field: "Age", want: int8(18)},
+		{name: "pointer", field: "LastName", want: &sql.NullString{String: "Quan", Valid: true}},
+		{name: "unknown", field: "Invalid", wantErr: err2.NewErrUnknownField("Invalid")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := val.Field(tt.field)
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.want, res)
+		})
+	}
+}
+
+func BenchmarkSetColumns(b *testing.B) {
+	r := newRegistry()
+	m, err := r.Get(&WideStruct{})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	cols := make([]string, 0, len(m.Columns))
+	row := make([]driver.Value, 0, len(m.Columns))
+	for _, fd := range m.Columns {
+		cols = append(cols, fd.ColName)
+		row = append(row, []byte("1"))
+	}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer db.Close()
+
+	benchmarks := []struct {
+		name    string
+		creator Creator
+	}{
+		{name: "reflect", creator: NewReflectValue},
+		{name: "unsafe", creator: NewUnsafeValue},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			// 一次性返回 b.N 行，避免把构造 rows 的开销算进来
+			mockRows := sqlmock.NewRows(cols)
+			for i := 0; i < b.N; i++ {
+				mockRows.AddRow(row...)
+			}
+			mock.ExpectQuery("SELECT .*").WillReturnRows(mockRows)
+			rows, err := db.Query("SELECT xxx")
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer rows.Close()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				rows.Next()
+				if err = bm.creator(&WideStruct{}, m).SetColumns(rows); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkField(b *testing.B) {
+	r := newRegistry()
+	m, err := r.Get(&WideStruct{})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	benchmarks := []struct {
+		name    string
+		creator Creator
+	}{
+		{name: "reflect", creator: NewReflectValue},
+		{name: "unsafe", creator: NewUnsafeValue},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			val := bm.creator(&WideStruct{}, m)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for _, fd := range m.Columns {
+					if _, err = val.Field(fd.GoName); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
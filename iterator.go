@@ -0,0 +1,49 @@
+package go_orm
+
+import (
+	"database/sql"
+	"go-orm/internal/model"
+	"go-orm/internal/valuer"
+)
+
+// Iterator 基于 *sql.Rows 的游标
+// 每次 Scan 只处理当前行，不会把整个结果集都加载到内存里
+// 用完之后一定要调用 Close
+type Iterator[T any] struct {
+	rows       *sql.Rows
+	model      *model.Model
+	valCreator valuer.Creator
+	err        error
+}
+
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	return it.rows.Next()
+}
+
+// Scan 将当前行映射为一个新的 *T
+func (it *Iterator[T]) Scan() (*T, error) {
+	if it.err != nil {
+		return nil, it.err
+	}
+	t := new(T)
+	val := it.valCreator(t, it.model)
+	if err := val.SetColumns(it.rows); err != nil {
+		it.err = err
+		return nil, err
+	}
+	return t, nil
+}
+
+func (it *Iterator[T]) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.rows.Err()
+}
+
+func (it *Iterator[T]) Close() error {
+	return it.rows.Close()
+}
@@ -1,13 +1,21 @@
 package go_orm
 
-import "context"
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"go-orm/internal/model"
+	"go-orm/internal/valuer"
+)
 
 type QueryContext struct {
+	// Type 语句类型，SELECT、INSERT、UPDATE、DELETE 或者 RAW
 	Type    string
 	Builder QueryBuilder
 }
 
 type QueryResult struct {
+	// Result 查询的时候是结果，执行的时候是 sql.Result
 	Result any
 
 	Err error
@@ -16,3 +24,113 @@ type QueryResult struct {
 type Handler func(ctx context.Context, qc *QueryContext) *QueryResult
 
 type MiddleWare func(next Handler) Handler
+
+// handle 用 middleware 把 root 包起来再执行
+// 所有的语句都要经过这里，保证只有一个拦截点
+func (c core) handle(ctx context.Context, qc *QueryContext, root Handler) *QueryResult {
+	for i := len(c.ms) - 1; i >= 0; i-- {
+		root = c.ms[i](root)
+	}
+	return root(ctx, qc)
+}
+
+// exec 执行不返回行的语句
+func exec(ctx context.Context, sess Session, c core, qc *QueryContext) Result {
+	var root Handler = func(ctx context.Context, qc *QueryContext) *QueryResult {
+		build, err := qc.Builder.Build()
+		if err != nil {
+			return &QueryResult{
+				Err: err,
+			}
+		}
+
+		res, err := sess.execContext(ctx, build.SQL, build.Args...)
+		return &QueryResult{
+			Result: res,
+			Err:    err,
+		}
+	}
+
+	qr := c.handle(ctx, qc, root)
+	if qr.Err != nil {
+		return Result{
+			err: qr.Err,
+		}
+	}
+
+	res, ok := qr.Result.(sql.Result)
+	if !ok {
+		return Result{
+			err: errors.New("类型错误"),
+		}
+	}
+	return Result{
+		res: res,
+	}
+}
+
+// query 执行返回行的语句，scan 负责处理 rows
+func query(ctx context.Context, sess Session, c core, qc *QueryContext,
+	scan func(rows *sql.Rows) (any, error)) *QueryResult {
+	var root Handler = func(ctx context.Context, qc *QueryContext) *QueryResult {
+		build, err := qc.Builder.Build()
+		if err != nil {
+			return &QueryResult{
+				Err: err,
+			}
+		}
+
+		rows, err := sess.queryContext(ctx, build.SQL, build.Args...)
+		if err != nil {
+			return &QueryResult{
+				Err: err,
+			}
+		}
+
+		res, err := scan(rows)
+		return &QueryResult{
+			Result: res,
+			Err:    err,
+		}
+	}
+
+	return c.handle(ctx, qc, root)
+}
+
+// scanOne 读取第一行，并且关闭 rows
+func scanOne[T any](rows *sql.Rows, creator valuer.Creator, m *model.Model) (*T, error) {
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, ErrNoRows
+	}
+
+	t := new(T)
+	if err := creator(t, m).SetColumns(rows); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// scanMulti 读取所有行，并且关闭 rows
+// 每处理一行之前检查一下 ctx，避免大结果集在超时后还继续映射
+func scanMulti[T any](ctx context.Context, rows *sql.Rows, creator valuer.Creator, m *model.Model) ([]*T, error) {
+	defer rows.Close()
+
+	res := make([]*T, 0, 8)
+	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		t := new(T)
+		if err := creator(t, m).SetColumns(rows); err != nil {
+			return nil, err
+		}
+		res = append(res, t)
+	}
+	return res, rows.Err()
+}
@@ -0,0 +1,97 @@
+package go_orm
+
+import (
+	"context"
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMiddleware_statementTypes(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+
+	var (
+		types   []string
+		sqls    []string
+		results []any
+	)
+	db, err := OpenDB(mockDB, DBUseReflectValuer(), DBWithMiddleware(func(next Handler) Handler {
+		return func(ctx context.Context, qc *QueryContext) *QueryResult {
+			q, err := qc.Builder.Build()
+			if err != nil {
+				return &QueryResult{Err: err}
+			}
+			types = append(types, qc.Type)
+			sqls = append(sqls, q.SQL)
+			res := next(ctx, qc)
+			results = append(results, res.Result)
+			return res
+		}
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE .*").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE .*").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("TRUNCATE .*").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	ctx := context.Background()
+	assert.NoError(t, NewInserter[TestModel](db).Values(&TestModel{}).Exec(ctx).(Result).Err())
+	assert.NoError(t, NewUpdater[TestModel](db).Set(Assign("Age", 1)).Exec(ctx).(Result).Err())
+	assert.NoError(t, NewDeleter[TestModel](db).Where(C("Id").EQ(1)).Exec(ctx).(Result).Err())
+	assert.NoError(t, RawQuery[TestModel](db, "TRUNCATE `test_model`;").Exec(ctx).(Result).Err())
+	_, err = NewSelector[TestModel](db).Get(ctx)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"INSERT", "UPDATE", "DELETE", "RAW", "SELECT"}, types)
+	assert.Equal(t, []string{
+		"INSERT INTO `test_model`(`id`,`first_name`,`age`,`last_name`)VALUES(?,?,?,?);",
+		"UPDATE `test_model` SET `age`=?;",
+		"DELETE FROM `test_model` WHERE `id` = ?;",
+		"TRUNCATE `test_model`;",
+		"SELECT * FROM `test_model`;",
+	}, sqls)
+	for _, res := range results[:4] {
+		_, ok := res.(sql.Result)
+		assert.True(t, ok)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRawQuerier_GetMulti(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+
+	db, err := OpenDB(mockDB, DBUseReflectValuer())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "first_name"})
+	rows.AddRow([]byte("1"), []byte("Liu"))
+	rows.AddRow([]byte("2"), []byte("Wang"))
+	mock.ExpectQuery("SELECT `id`,`first_name` FROM `test_model` WHERE `age` > \\?").
+		WithArgs(18).
+		WillReturnRows(rows)
+
+	res, err := RawQuery[TestModel](db, "SELECT `id`,`first_name` FROM `test_model` WHERE `age` > ?", 18).
+		GetMulti(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []*TestModel{
+		{Id: 1, FirstName: "Liu"},
+		{Id: 2, FirstName: "Wang"},
+	}, res)
+}
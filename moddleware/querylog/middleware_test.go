@@ -3,11 +3,15 @@ package querylog
 import (
 	"context"
 	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
 	go_orm "go-orm"
 	"testing"
 )
 
 type TestModel struct {
+	Id   int64
+	Name string
 }
 
 func TestMiddlewareBuilder_Build(t *testing.T) {
@@ -30,3 +34,27 @@ func TestMiddlewareBuilder_Build(t *testing.T) {
 
 	fmt.Println(get)
 }
+
+func TestMiddlewareBuilder_Insert(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var logs []string
+	build := &MiddlewareBuilder{}
+	orm, err := go_orm.OpenDB(db, go_orm.DBWithMiddleware(
+		build.LogFunc(func(sql string, args ...any) {
+			logs = append(logs, sql)
+		}).Build()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	res := go_orm.NewInserter[TestModel](orm).Values(&TestModel{Id: 1, Name: "liu"}).Exec(context.Background())
+	_, err = res.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"INSERT INTO `test_model`(`id`,`name`)VALUES(?,?);"}, logs)
+}
@@ -0,0 +1,75 @@
+package go_orm
+
+import (
+	"context"
+	"math"
+)
+
+// Page 分页查询的结果
+type Page[T any] struct {
+	Items []*T
+	// Total 总行数
+	Total int64
+	// Page 当前页码，从 1 开始
+	Page int32
+	Size int32
+}
+
+// Paginate 分页查询，同时返回总行数
+// 会执行两个查询，一个是 COUNT(*)，一个是当前页的数据，都会经过 middleware
+// COUNT(*) 保留 WHERE、JOIN、GROUP BY 和 HAVING，去掉 ORDER BY、LIMIT 和 OFFSET
+func (s *Selector[T]) Paginate(ctx context.Context, page, size int32) (*Page[T], error) {
+	if page <= 0 {
+		return nil, ErrInvalidPage
+	}
+	if size <= 0 {
+		return nil, ErrInvalidPageSize
+	}
+
+	offset := int64(page-1) * int64(size)
+	if offset > math.MaxInt32 {
+		return nil, ErrPageOutOfRange
+	}
+
+	total, err := Scalar[int64](ctx, s.countSelector())
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Page[T]{
+		Items: []*T{},
+		Total: total,
+		Page:  page,
+		Size:  size,
+	}
+	// 没有数据的时候不需要再查一次
+	if offset >= total {
+		return res, nil
+	}
+
+	// 在副本上设置 OFFSET 和 LIMIT，不影响 s
+	q := s.clone()
+	q.offset, q.limit = int32(offset), size
+	res.Items, err = q.GetMulti(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// countSelector 构造 COUNT(*) 查询
+// 有 GROUP BY、HAVING 或者 DISTINCT 的时候，需要把原来的查询作为子查询才能算出正确的行数
+func (s *Selector[T]) countSelector() *Selector[T] {
+	sub := s.clone()
+	sub.orderBy, sub.limit, sub.offset, sub.after = nil, 0, 0, ""
+	sub.lock, sub.wait = lockModeNone, lockWaitDefault
+	if len(s.groupBy) > 0 || len(s.having) > 0 || s.distinct {
+		// WITH 放在最外层
+		sub.ctes, sub.recursive = nil, false
+		outer := NewSelector[T](s.sess).Select(Count("*")).FromTable(sub.AsSubquery("sub"))
+		outer.ctes, outer.recursive = s.ctes, s.recursive
+		return outer
+	}
+	sub.columns = []Selectable{Count("*")}
+	return sub
+}
@@ -0,0 +1,157 @@
+package go_orm
+
+import (
+	"context"
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math"
+	"testing"
+)
+
+func TestSelector_countSelector(t *testing.T) {
+	db := memoryDB()
+	t1 := TableOf[Order]().As("t1")
+	t2 := TableOf[OrderDetail]().As("t2")
+	tests := []struct {
+		name string
+		s    *Selector[Order]
+		want *Query
+	}{
+		{
+			name: "where",
+			s:    NewSelector[Order](db).Where(C("Id").GT(10)).OrderBy(Desc("Id")).Limit(10).Offset(20),
+			want: &Query{
+				SQL:  "SELECT COUNT(*) FROM `order` WHERE `id` > ?;",
+				Args: []any{10},
+			},
+		},
+		{
+			name: "join",
+			s: NewSelector[Order](db).Select(t1.C("Id"), t2.C("ItemId")).
+				FromTable(t1.Join(t2).On(t1.C("Id").EQ(t2.C("OrderId")))).Where(t2.C("ItemId").EQ(3)).
+				OrderBy(t1.C("Id").Asc()).Limit(10),
+			want: &Query{
+				SQL:  "SELECT COUNT(*) FROM `order` AS `t1` JOIN `order_detail` AS `t2` ON `t1`.`id` = `t2`.`order_id` WHERE `t2`.`item_id` = ?;",
+				Args: []any{3},
+			},
+		},
+		{
+			name: "group by",
+			s: NewSelector[Order](db).Select(C("UsingCol1"), Count("Id")).GroupBy(C("UsingCol1")).
+				Having(Count("Id").GT(1)).OrderBy(Asc("UsingCol1")).Limit(10),
+			want: &Query{
+				SQL: "SELECT COUNT(*) FROM (SELECT `using_col1`,COUNT(`id`) FROM `order` GROUP BY `using_col1`" +
+					" HAVING COUNT(`id`) > ?) AS `sub`;",
+				Args: []any{1},
+			},
+		},
+		{
+			name: "having without group by",
+			s:    NewSelector[Order](db).Select(Count("Id")).Having(Count("Id").GT(1)),
+			want: &Query{
+				SQL:  "SELECT COUNT(*) FROM (SELECT COUNT(`id`) FROM `order` HAVING COUNT(`id`) > ?) AS `sub`;",
+				Args: []any{1},
+			},
+		},
+		{
+			name: "distinct",
+			s:    NewSelector[Order](db).Select(C("UsingCol1")).Distinct().Limit(10),
+			want: &Query{
+				SQL: "SELECT COUNT(*) FROM (SELECT DISTINCT `using_col1` FROM `order`) AS `sub`;",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.countSelector().Build()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSelector_Paginate(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+
+	var types []string
+	db, err := OpenDB(mockDB, DBWithMiddleware(func(next Handler) Handler {
+		return func(ctx context.Context, qc *QueryContext) *QueryResult {
+			types = append(types, qc.Type)
+			return next(ctx, qc)
+		}
+	}))
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `test_model` WHERE `age` > \\?;").
+		WithArgs(18).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(3))
+	mock.ExpectQuery("SELECT \\* FROM `test_model` WHERE `age` > \\? ORDER BY `id` ASC LIMIT \\? OFFSET \\?;").
+		WithArgs(18, 2, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "first_name", "age", "last_name"}).
+			AddRow(3, "Liu", 20, nil))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `test_model` WHERE `age` > \\?;").
+		WithArgs(18).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(3))
+	mock.ExpectCommit()
+
+	ctx := context.Background()
+	tx, err := db.Begin(ctx, &sql.TxOptions{})
+	require.NoError(t, err)
+
+	s := NewSelector[TestModel](tx).Where(C("Age").GT(18)).OrderBy(Asc("Id"))
+	page, err := s.Paginate(ctx, 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, &Page[TestModel]{
+		Items: []*TestModel{{Id: 3, FirstName: "Liu", Age: 20}},
+		Total: 3,
+		Page:  2,
+		Size:  2,
+	}, page)
+	// Paginate 不会修改原来的查询
+	q, err := s.Build()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `test_model` WHERE `age` > ? ORDER BY `id` ASC;", q.SQL)
+
+	// 超出范围的时候不会查询数据
+	page, err = s.Paginate(ctx, 3, 2)
+	require.NoError(t, err)
+	assert.Equal(t, &Page[TestModel]{Items: []*TestModel{}, Total: 3, Page: 3, Size: 2}, page)
+
+	require.NoError(t, tx.Commit())
+	assert.Equal(t, []string{"SELECT", "SELECT", "SELECT"}, types)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	_, err = NewSelector[TestModel](db).Paginate(ctx, 0, 10)
+	assert.Equal(t, ErrInvalidPage, err)
+	_, err = NewSelector[TestModel](db).Paginate(ctx, 1, 0)
+	assert.Equal(t, ErrInvalidPageSize, err)
+	_, err = NewSelector[TestModel](db).Paginate(ctx, math.MaxInt32, 2)
+	assert.Equal(t, ErrPageOutOfRange, err)
+}
+
+func TestSelector_Paginate_sqlite(t *testing.T) {
+	db := sqliteDB(t)
+	ctx := context.Background()
+	res := NewInserter[TestModel](db).Values(
+		&TestModel{Id: 1, FirstName: "a", Age: 20},
+		&TestModel{Id: 2, FirstName: "b", Age: 18},
+		&TestModel{Id: 3, FirstName: "c", Age: 20},
+		&TestModel{Id: 4, FirstName: "d", Age: 30},
+	).Exec(ctx)
+	require.NoError(t, res.(Result).Err())
+
+	page, err := NewSelector[TestModel](db).Where(C("Age").GTE(20)).OrderBy(Desc("Id")).Paginate(ctx, 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), page.Total)
+	assert.Equal(t, []*TestModel{{Id: 4, FirstName: "d", Age: 30}, {Id: 3, FirstName: "c", Age: 20}}, page.Items)
+
+	page, err = NewSelector[TestModel](db).Select(C("Age")).GroupBy(C("Age")).OrderBy(Asc("Age")).Paginate(ctx, 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), page.Total)
+	assert.Equal(t, []*TestModel{{Age: 30}}, page.Items)
+}
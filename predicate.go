@@ -3,12 +3,29 @@ package go_orm
 type op string
 
 const (
-	opEQ  = "="
-	opLT  = "<"
-	opGT  = ">"
-	opNOT = "NOT"
-	opAND = "AND"
-	opOR  = "OR"
+	opEQ        = "="
+	opNEQ       = "!="
+	opLT        = "<"
+	opLTE       = "<="
+	opGT        = ">"
+	opGTE       = ">="
+	opIN        = "IN"
+	opNotIN     = "NOT IN"
+	opLIKE      = "LIKE"
+	opNotLIKE   = "NOT LIKE"
+	opBETWEEN   = "BETWEEN"
+	opIsNull    = "IS NULL"
+	opIsNotNull = "IS NOT NULL"
+	opExists    = "EXISTS"
+	opNotExists = "NOT EXISTS"
+	opNOT       = "NOT"
+	opAND       = "AND"
+	opOR        = "OR"
+
+	opAdd   = "+"
+	opSub   = "-"
+	opMulti = "*"
+	opDiv   = "/"
 )
 
 type Predicate struct {
@@ -24,6 +41,8 @@ func (o op) String() string {
 func (p Predicate) expr() {}
 
 type Column struct {
+	// table 为 nil 的时候使用 Selector 的 T 来解析
+	table TableReference
 	name  string
 	alias string
 }
@@ -45,32 +64,160 @@ type Value struct {
 
 func (v Value) expr() {}
 
+// values 多个值，用于 IN 和 BETWEEN
+type values struct {
+	vals []any
+}
+
+func (v values) expr() {}
+
 // C field
 func C(name string) Column {
 	return Column{name: name}
 }
 
+func (c Column) Asc() OrderBy {
+	return OrderBy{
+		expr:  c,
+		order: "ASC",
+	}
+}
+
+func (c Column) Desc() OrderBy {
+	return OrderBy{
+		expr:  c,
+		order: "DESC",
+	}
+}
+
 func (c Column) EQ(val any) Predicate {
+	return newPredicate(c, opEQ, val)
+}
+
+func (c Column) GT(val any) Predicate {
+	return newPredicate(c, opGT, val)
+}
+
+func (c Column) LT(val any) Predicate {
+	return newPredicate(c, opLT, val)
+}
+
+func (c Column) NEQ(val any) Predicate {
+	return newPredicate(c, opNEQ, val)
+}
+
+func (c Column) GTE(val any) Predicate {
+	return newPredicate(c, opGTE, val)
+}
+
+func (c Column) LTE(val any) Predicate {
+	return newPredicate(c, opLTE, val)
+}
+
+// In 空的 IN 列表永远不成立
+// 只传入一个子查询的时候，对应 IN (SELECT ...)
+func (c Column) In(vals ...any) Predicate {
 	return Predicate{
 		left:  c,
-		op:    opEQ,
-		right: Value{val: val},
+		op:    opIN,
+		right: inValues(vals),
 	}
 }
 
-func (c Column) GT(val any) Predicate {
+// NotIn 空的 NOT IN 列表永远成立
+func (c Column) NotIn(vals ...any) Predicate {
 	return Predicate{
 		left:  c,
-		op:    opGT,
-		right: Value{val: val},
+		op:    opNotIN,
+		right: inValues(vals),
 	}
 }
 
-func (c Column) LT(val any) Predicate {
+func inValues(vals []any) Expression {
+	if len(vals) == 1 {
+		if sub, ok := vals[0].(SubqueryBuilder); ok {
+			return sub.subquery()
+		}
+	}
+	return values{vals: vals}
+}
+
+func (c Column) Like(pattern string) Predicate {
+	return Predicate{
+		left:  c,
+		op:    opLIKE,
+		right: Value{val: pattern},
+	}
+}
+
+func (c Column) NotLike(pattern string) Predicate {
 	return Predicate{
 		left:  c,
-		op:    opLT,
-		right: Value{val: val},
+		op:    opNotLIKE,
+		right: Value{val: pattern},
+	}
+}
+
+func (c Column) Between(start, end any) Predicate {
+	return Predicate{
+		left:  c,
+		op:    opBETWEEN,
+		right: values{vals: []any{start, end}},
+	}
+}
+
+func (c Column) IsNull() Predicate {
+	return Predicate{
+		left: c,
+		op:   opIsNull,
+	}
+}
+
+func (c Column) IsNotNull() Predicate {
+	return Predicate{
+		left: c,
+		op:   opIsNotNull,
+	}
+}
+
+func (c Column) Add(val any) MathExpr {
+	return MathExpr{
+		left:  c,
+		op:    opAdd,
+		right: valueOf(val),
+	}
+}
+
+func (c Column) Sub(val any) MathExpr {
+	return MathExpr{
+		left:  c,
+		op:    opSub,
+		right: valueOf(val),
+	}
+}
+
+func (c Column) Multi(val any) MathExpr {
+	return MathExpr{
+		left:  c,
+		op:    opMulti,
+		right: valueOf(val),
+	}
+}
+
+func (c Column) Div(val any) MathExpr {
+	return MathExpr{
+		left:  c,
+		op:    opDiv,
+		right: valueOf(val),
+	}
+}
+
+// newPredicate val 可以是另外一个表达式，例如 C("Age").GT(C("MinAge"))
+func newPredicate(left Expression, op op, val any) Predicate {
+	return Predicate{
+		left:  left,
+		op:    op,
+		right: valueOf(val),
 	}
 }
 
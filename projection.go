@@ -0,0 +1,67 @@
+package go_orm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// Scalar 执行查询并读取第一行第一列
+// 适用于聚合查询，例如 Scalar[float64](ctx, NewSelector[User](db).Select(Avg("Age")))
+func Scalar[V any, T any](ctx context.Context, s *Selector[T]) (V, error) {
+	var zero V
+	res := query(ctx, s.sess, s.core, &QueryContext{
+		Type:    "SELECT",
+		Builder: s,
+	}, func(rows *sql.Rows) (any, error) {
+		defer rows.Close()
+		if !rows.Next() {
+			if err := rows.Err(); err != nil {
+				return nil, err
+			}
+			return nil, ErrNoRows
+		}
+		var v V
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	})
+
+	if res.Err != nil {
+		return zero, res.Err
+	}
+
+	v, ok := res.Result.(V)
+	if !ok {
+		return zero, errors.New("类型错误")
+	}
+	return v, nil
+}
+
+// Project 执行查询并把结果映射到另外一个结构体 R
+// R 的列名和查询返回的列名对应，聚合函数和表达式可以通过 As 指定别名，
+// 例如 Avg("Age").As("avg_age") 对应 R 中的 AvgAge 字段
+func Project[R any, T any](ctx context.Context, s *Selector[T]) ([]*R, error) {
+	m, err := s.r.Get(new(R))
+	if err != nil {
+		return nil, err
+	}
+
+	res := query(ctx, s.sess, s.core, &QueryContext{
+		Type:    "SELECT",
+		Builder: s,
+	}, func(rows *sql.Rows) (any, error) {
+		return scanMulti[R](ctx, rows, s.valCreator, m)
+	})
+
+	if res.Err != nil {
+		return nil, res.Err
+	}
+
+	rs, ok := res.Result.([]*R)
+	if !ok {
+		return nil, errors.New("类型错误")
+	}
+	return rs, nil
+}
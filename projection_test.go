@@ -0,0 +1,69 @@
+package go_orm
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestScalar(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+
+	db, err := OpenDB(mockDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mock.ExpectQuery("SELECT AVG\\(`age`\\) FROM `test_model` WHERE `id` > \\?;").
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"AVG(`age`)"}).AddRow([]byte("18.5")))
+	avg, err := Scalar[float64](context.Background(), NewSelector[TestModel](db).Select(Avg("Age")).Where(C("Id").GT(10)))
+	assert.NoError(t, err)
+	assert.Equal(t, 18.5, avg)
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `test_model`;").
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}))
+	_, err = Scalar[int64](context.Background(), NewSelector[TestModel](db).Select(Count("*")))
+	assert.Equal(t, ErrNoRows, err)
+}
+
+func TestProject(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+
+	db, err := OpenDB(mockDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type AgeStat struct {
+		Age    int8
+		Cnt    int64
+		AvgId  float64
+		LowerN string `orm:"column=name"`
+	}
+
+	rows := sqlmock.NewRows([]string{"age", "cnt", "avg_id", "name"})
+	rows.AddRow([]byte("18"), []byte("2"), []byte("1.5"), []byte("liu"))
+	rows.AddRow([]byte("30"), []byte("1"), []byte("3"), []byte("wang"))
+	mock.ExpectQuery("SELECT `age`,COUNT\\(`id`\\) as `cnt`,AVG\\(`id`\\) as `avg_id`,MIN\\(LOWER\\(`first_name`\\)\\) as `name` " +
+		"FROM `test_model` GROUP BY `age`;").
+		WillReturnRows(rows)
+
+	res, err := Project[AgeStat](context.Background(), NewSelector[TestModel](db).
+		Select(C("Age"), Count("Id").As("cnt"), Avg("Id").As("avg_id"), Fn("MIN", Fn("LOWER", C("FirstName"))).As("name")).
+		GroupBy(C("Age")))
+	assert.NoError(t, err)
+	assert.Equal(t, []*AgeStat{
+		{Age: 18, Cnt: 2, AvgId: 1.5, LowerN: "liu"},
+		{Age: 30, Cnt: 1, AvgId: 3, LowerN: "wang"},
+	}, res)
+}
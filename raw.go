@@ -0,0 +1,87 @@
+package go_orm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// RawQuerier 直接执行用户写好的 SQL
+// 同样会经过 middleware，QueryContext.Type 为 RAW
+type RawQuerier[T any] struct {
+	core
+	sess Session
+	sql  string
+	args []any
+}
+
+func RawQuery[T any](sess Session, query string, args ...any) *RawQuerier[T] {
+	return &RawQuerier[T]{
+		core: sess.getCore(),
+		sess: sess,
+		sql:  query,
+		args: args,
+	}
+}
+
+func (r *RawQuerier[T]) Build() (*Query, error) {
+	return &Query{
+		SQL:  r.sql,
+		Args: r.args,
+	}, nil
+}
+
+func (r *RawQuerier[T]) Exec(ctx context.Context) sql.Result {
+	return exec(ctx, r.sess, r.core, &QueryContext{
+		Type:    "RAW",
+		Builder: r,
+	})
+}
+
+func (r *RawQuerier[T]) Get(ctx context.Context) (*T, error) {
+	m, err := r.r.Get(new(T))
+	if err != nil {
+		return nil, err
+	}
+
+	res := query(ctx, r.sess, r.core, &QueryContext{
+		Type:    "RAW",
+		Builder: r,
+	}, func(rows *sql.Rows) (any, error) {
+		return scanOne[T](rows, r.valCreator, m)
+	})
+
+	if res.Err != nil {
+		return nil, res.Err
+	}
+
+	t, ok := res.Result.(*T)
+	if !ok {
+		return nil, errors.New("类型错误")
+	}
+	return t, nil
+}
+
+func (r *RawQuerier[T]) GetMulti(ctx context.Context) ([]*T, error) {
+	m, err := r.r.Get(new(T))
+	if err != nil {
+		return nil, err
+	}
+
+	res := query(ctx, r.sess, r.core, &QueryContext{
+		Type:    "RAW",
+		Builder: r,
+	}, func(rows *sql.Rows) (any, error) {
+		return scanMulti[T](ctx, rows, r.valCreator, m)
+	})
+
+	if res.Err != nil {
+		return nil, res.Err
+	}
+
+	ts, ok := res.Result.([]*T)
+	if !ok {
+		return nil, errors.New("类型错误")
+	}
+	return ts, nil
+}
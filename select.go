@@ -2,29 +2,32 @@ package go_orm
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	err2 "go-orm/internal/err"
-	model2 "go-orm/internal/model"
-	"strings"
 )
 
 type Selector[T any] struct {
 	builder
-	sb      strings.Builder
-	Args    []any
-	table   string
+	table   TableReference
 	where   []Predicate
 	having  []Predicate
 	columns []Selectable
-	groupBy []Column
-	orderBy []OrderBy
-	limit   int32
-	offset  int32
+	// distinct SELECT DISTINCT
+	distinct bool
+	groupBy  []Column
+	orderBy  []OrderBy
+	limit    int32
+	offset   int32
+	// after 游标分页中上一页的游标
+	after string
+	lock  lockMode
+	wait  lockWait
+	// ctes WITH 定义的命名子查询
+	ctes      []CTE
+	recursive bool
 
-	model *model2.Model
-	//db    *DB
 	sess Session
-	core
 }
 
 type Selectable interface {
@@ -32,9 +35,12 @@ type Selectable interface {
 }
 
 func NewSelector[T any](sess Session) *Selector[T] {
+	c := sess.getCore()
 	return &Selector[T]{
+		builder: builder{
+			core: c,
+		},
 		sess: sess,
-		core: sess.getCore(),
 	}
 }
 
@@ -43,7 +49,19 @@ func (s *Selector[T]) Select(cols ...Selectable) *Selector[T] {
 	return s
 }
 
+// Distinct 去重，对应 SELECT DISTINCT
+func (s *Selector[T]) Distinct() *Selector[T] {
+	s.distinct = true
+	return s
+}
+
+// From 直接指定表名，支持 db.table_name 的写法
 func (s *Selector[T]) From(table string) *Selector[T] {
+	return s.FromTable(TableNamed(table))
+}
+
+// FromTable 指定表，可以是 TableOf、TableNamed、Join、子查询或者 CTE
+func (s *Selector[T]) FromTable(table TableReference) *Selector[T] {
 	s.table = table
 	return s
 }
@@ -78,157 +96,266 @@ func (s *Selector[T]) Offset(o int32) *Selector[T] {
 	return s
 }
 
+// ForUpdate 对查询到的行加排他锁，需要在事务中使用
+func (s *Selector[T]) ForUpdate() *Selector[T] {
+	s.lock = lockModeUpdate
+	return s
+}
+
+// ForShare 对查询到的行加共享锁，需要在事务中使用
+func (s *Selector[T]) ForShare() *Selector[T] {
+	s.lock = lockModeShare
+	return s
+}
+
+// NoWait 拿不到锁的时候直接返回错误，而不是等待
+func (s *Selector[T]) NoWait() *Selector[T] {
+	s.wait = lockWaitNoWait
+	return s
+}
+
+// SkipLocked 跳过已经被其它事务锁住的行，常用于任务队列
+func (s *Selector[T]) SkipLocked() *Selector[T] {
+	s.wait = lockWaitSkipLocked
+	return s
+}
+
+// clone 复制一份查询，已经构造的内容不会复制，修改副本不会影响原来的查询
+func (s *Selector[T]) clone() *Selector[T] {
+	c := *s
+	c.builder = builder{
+		core: s.core,
+	}
+	return &c
+}
+
 func (s *Selector[T]) Build() (*Query, error) {
+	s.argOffset = 0
+	if err := s.build(); err != nil {
+		return nil, err
+	}
+	s.sb.WriteByte(';')
+	return &Query{
+		SQL:  s.sb.String(),
+		Args: s.args,
+	}, nil
+}
+
+// buildSubquery 作为子查询构造，不带结尾的分号
+// offset 是外层查询已有的参数个数，用于生成正确的占位符
+func (s *Selector[T]) buildSubquery(offset int) (*Query, error) {
+	s.argOffset = offset
+	if err := s.build(); err != nil {
+		return nil, err
+	}
+	return &Query{
+		SQL:  s.sb.String(),
+		Args: s.args,
+	}, nil
+}
+
+// subqueryColumn 外层查询通过别名或者字段名引用子查询的列
+// 外层的 SELECT 列表会先于 FROM 构造，所以这里不能依赖 s.m
+func (s *Selector[T]) subqueryColumn(name string) (string, bool) {
+	if len(s.columns) == 0 {
+		return s.fieldColumn(nil, name)
+	}
+
+	for _, col := range s.columns {
+		if alias := aliasOf(col); alias != "" {
+			if alias == name {
+				return name, true
+			}
+			continue
+		}
+		if c, ok := col.(Column); ok && c.name == name {
+			return s.fieldColumn(c.table, name)
+		}
+	}
+	return "", false
+}
+
+// aliasOf 返回 SELECT 列表中的别名，没有别名的时候返回空字符串
+func aliasOf(col Selectable) string {
+	switch c := col.(type) {
+	case Column:
+		return c.alias
+	case Aggregate:
+		return c.alias
+	case MathExpr:
+		return c.alias
+	case FuncExpr:
+		return c.alias
+	case Subquery:
+		return c.alias
+	case Window:
+		return c.alias
+	default:
+		return ""
+	}
+}
+
+// fieldColumn 字段名转列名，table 为 nil 或者没有实体的时候使用 T
+func (s *Selector[T]) fieldColumn(table TableReference, name string) (string, bool) {
+	var entity any = new(T)
+	if t, ok := table.(Table); ok && t.entity != nil {
+		entity = t.entity
+	}
+	m, err := s.r.Get(entity)
+	if err != nil {
+		return "", false
+	}
+	fd, ok := m.FieldMap[name]
+	if !ok {
+		return "", false
+	}
+	return fd.ColName, true
+}
+
+func (s *Selector[T]) standalone() bool {
+	return len(s.orderBy) == 0 && s.limit == 0 && s.offset == 0
+}
+
+// AsSubquery 作为子查询使用
+// 用在 FromTable 里面的时候必须指定别名
+func (s *Selector[T]) AsSubquery(alias string) Subquery {
+	return Subquery{
+		s:     s,
+		alias: alias,
+	}
+}
+
+func (s *Selector[T]) subquery() Subquery {
+	return s.AsSubquery("")
+}
+
+func (s *Selector[T]) build() error {
 	var (
 		t   = new(T)
 		err error
 	)
-	s.model, err = s.r.Get(t)
+	s.reset()
+	s.m, err = s.r.Get(t)
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	if err = s.buildWith(s.ctes, s.recursive); err != nil {
+		return err
 	}
 
 	s.sb.WriteString("SELECT ")
+	if s.distinct {
+		s.sb.WriteString("DISTINCT ")
+	}
 	if err = s.buildColumns(); err != nil {
-		return nil, err
+		return err
 	}
 	s.sb.WriteString(" FROM ")
 
-	s.buildTableName()
+	if err = s.buildTable(s.table); err != nil {
+		return err
+	}
 
 	if err = s.buildWhere(); err != nil {
-		return nil, err
+		return err
 	}
 
 	if err = s.buildGroupBy(); err != nil {
-		return nil, err
+		return err
 	}
 
 	if err = s.buildHaving(); err != nil {
-		return nil, err
+		return err
 	}
 
 	if err = s.buildOrderBy(); err != nil {
-		return nil, err
+		return err
 	}
 
 	if s.limit > 0 {
-		s.sb.WriteString(" LIMIT ?")
-		s.addArgs(s.limit)
+		s.sb.WriteString(" LIMIT ")
+		s.parameter(s.limit)
 	}
 
 	if s.offset > 0 {
-		s.sb.WriteString(" OFFSET ?")
-		s.addArgs(s.offset)
+		s.sb.WriteString(" OFFSET ")
+		s.parameter(s.offset)
 	}
 
-	s.sb.WriteByte(';')
-	return &Query{
-		SQL:  s.sb.String(),
-		Args: s.Args,
-	}, nil
-}
-
-func (s *Selector[T]) buildExpression(expression Expression) error {
-	switch expr := expression.(type) {
-	case nil:
-		return nil
-	case Column:
-		s.sb.WriteByte('`')
-		f, ok := s.model.FieldMap[expr.name]
-		if !ok {
-			return errors.New("字段不存在")
-		}
-		s.sb.WriteString(f.ColName)
-		s.sb.WriteByte('`')
-	case Value:
-		s.sb.WriteByte('?')
-		if s.Args == nil {
-			s.Args = make([]any, 0, 8)
-		}
-		s.Args = append(s.Args, expr.val)
-	case Predicate:
-		_, ok := expr.left.(Predicate)
-		if ok {
-			s.sb.WriteByte('(')
-		}
-		err := s.buildExpression(expr.left)
-		if err != nil {
-			return err
-		}
-		if ok {
-			s.sb.WriteByte(')')
-		}
-		if expr.op.String() != "NOT" {
-			s.sb.WriteByte(' ')
-		}
-
-		s.sb.WriteString(expr.op.String())
-		s.sb.WriteByte(' ')
-
-		_, ok = expr.right.(Predicate)
-		if ok {
-			s.sb.WriteByte('(')
-		}
-
-		err = s.buildExpression(expr.right)
-		if ok {
-			s.sb.WriteByte(')')
-		}
-		return err
-	default:
-		return errors.New("不支持该表达式")
+	if s.lock != lockModeNone {
+		return s.core.dialect.buildLock(&s.builder, s.lock, s.wait)
+	}
+	if s.wait != lockWaitDefault {
+		return ErrLockOptionWithoutLock
 	}
 	return nil
 }
 
 func (s *Selector[T]) Get(ctx context.Context) (*T, error) {
-	var root Handler = func(ctx context.Context, qc *QueryContext) *QueryResult {
-		build, err := qc.Builder.Build()
-		if err != nil {
-			return &QueryResult{
-				Err: err,
-			}
-		}
-
-		rows, err := s.sess.queryContext(ctx, build.SQL, s.Args...)
-		if err != nil {
-			return &QueryResult{
-				Err: err,
-			}
-		}
-		t := new(T)
+	res := query(ctx, s.sess, s.core, &QueryContext{
+		Type:    "SELECT",
+		Builder: s,
+	}, func(rows *sql.Rows) (any, error) {
+		return scanOne[T](rows, s.valCreator, s.m)
+	})
 
-		val := s.valCreator(t, s.model)
-		return &QueryResult{
-			Result: t,
-			Err:    val.SetColumns(rows),
-		}
+	if res.Err != nil {
+		return nil, res.Err
 	}
 
-	for i := len(s.ms) - 1; i >= 0; i-- {
-		root = s.ms[i](root)
+	t, ok := res.Result.(*T)
+	if !ok {
+		return nil, errors.New("类型错误")
 	}
 
-	res := root(ctx, &QueryContext{
+	return t, nil
+}
+
+func (s *Selector[T]) GetMulti(ctx context.Context) ([]*T, error) {
+	res := query(ctx, s.sess, s.core, &QueryContext{
 		Type:    "SELECT",
 		Builder: s,
+	}, func(rows *sql.Rows) (any, error) {
+		return scanMulti[T](ctx, rows, s.valCreator, s.m)
 	})
 
 	if res.Err != nil {
 		return nil, res.Err
 	}
 
-	t, ok := res.Result.(*T)
+	ts, ok := res.Result.([]*T)
 	if !ok {
 		return nil, errors.New("类型错误")
 	}
 
-	return t, nil
+	return ts, nil
 }
 
-func (s *Selector[T]) GetMulti(ctx context.Context) ([]*T, error) {
-	return nil, nil
+// Iter 返回一个游标，逐行读取结果集
+// 适合结果集很大，不能一次性全部加载到内存的场景
+func (s *Selector[T]) Iter(ctx context.Context) (*Iterator[T], error) {
+	res := query(ctx, s.sess, s.core, &QueryContext{
+		Type:    "SELECT",
+		Builder: s,
+	}, func(rows *sql.Rows) (any, error) {
+		return rows, nil
+	})
+
+	if res.Err != nil {
+		return nil, res.Err
+	}
+
+	rows, ok := res.Result.(*sql.Rows)
+	if !ok {
+		return nil, errors.New("类型错误")
+	}
+
+	return &Iterator[T]{
+		rows:       rows,
+		model:      s.m,
+		valCreator: s.valCreator,
+	}, nil
 }
 
 func (s *Selector[T]) buildColumns() error {
@@ -241,88 +368,66 @@ func (s *Selector[T]) buildColumns() error {
 			}
 			switch c := column.(type) {
 			case Column:
-				if err := s.buildColumn(c.name, c.alias); err != nil {
+				if err := s.buildExpression(c); err != nil {
 					return err
 				}
+				s.buildAs(c.alias)
 			case Aggregate:
-				if err := s.buildAggregate(c); err != nil {
+				if err := s.buildExpression(c); err != nil {
 					return err
 				}
+				s.buildAs(c.alias)
 			case RawExpr:
-				s.sb.WriteString(c.raw)
-				if len(c.args) > 0 {
-					s.Args = append(s.Args, c.args)
+				if err := s.buildExpression(c); err != nil {
+					return err
 				}
+			case MathExpr:
+				if err := s.buildExpression(c); err != nil {
+					return err
+				}
+				s.buildAs(c.alias)
+			case FuncExpr:
+				if err := s.buildExpression(c); err != nil {
+					return err
+				}
+				s.buildAs(c.alias)
+			case Subquery:
+				if err := s.buildExpression(c); err != nil {
+					return err
+				}
+				s.buildAs(c.alias)
+			case Window:
+				if err := s.buildWindow(c); err != nil {
+					return err
+				}
+				s.buildAs(c.alias)
+			default:
+				return err2.NewErrUnsupportedSelectable(c)
 			}
 		}
 	}
 	return nil
 }
 
-func (s *Selector[T]) buildColumn(name, alias string) error {
-	s.sb.WriteByte('`')
-	fd, ok := s.model.FieldMap[name]
-	if !ok {
-		return err2.NewErrUnknownColumn(name)
-	}
-	s.sb.WriteString(fd.ColName)
-	s.sb.WriteByte('`')
-
-	s.buildAs(alias)
-	return nil
-}
-
 func (s *Selector[T]) buildAs(alias string) {
 	if alias != "" {
 		s.sb.WriteString(" as ")
-		s.sb.WriteByte('`')
-		s.sb.WriteString(alias)
-		s.sb.WriteByte('`')
-	}
-}
-
-func (s *Selector[T]) buildAggregate(c Aggregate) error {
-	s.sb.WriteString(c.fn)
-	s.sb.WriteByte('(')
-
-	fd, ok := s.model.FieldMap[c.arg]
-	if !ok {
-		return err2.NewErrUnknownColumn(c.arg)
+		s.quote(alias)
 	}
-	s.sb.WriteByte('`')
-	s.sb.WriteString(fd.ColName)
-	s.sb.WriteByte('`')
-	s.sb.WriteByte(')')
-
-	s.buildAs(c.alias)
-
-	return nil
 }
 
-func (s *Selector[T]) buildTableName() {
-	s.sb.WriteByte('`')
-	if s.table == "" {
-		s.sb.WriteString(s.model.TableName)
-	} else {
-		// 处理 db.table_name 的情况
-		segs := strings.SplitN(s.table, ".", 2)
-		if len(segs) == 2 {
-			s.sb.WriteString(segs[0])
-			s.sb.WriteByte('`')
-			s.sb.WriteByte('.')
-			s.sb.WriteByte('`')
-			s.sb.WriteString(segs[1])
-		} else {
-			s.sb.WriteString(s.table)
+func (s *Selector[T]) buildWhere() error {
+	ps := s.where
+	if s.after != "" {
+		p, err := s.cursorPredicate()
+		if err != nil {
+			return err
 		}
+		ps = append(ps[:len(ps):len(ps)], p)
 	}
-	s.sb.WriteByte('`')
-}
-
-func (s *Selector[T]) buildWhere() error {
-	if len(s.where) > 0 {
+	if len(ps) > 0 {
 		s.sb.WriteString(" WHERE ")
-		return s.buildPredicates(s.where)
+		return s.buildPredicates(ps)
 	}
 	return nil
 }
@@ -335,7 +440,7 @@ func (s *Selector[T]) buildGroupBy() error {
 				s.sb.WriteByte(',')
 			}
 
-			if err := s.buildColumn(c.name, ""); err != nil {
+			if err := s.buildExpression(c); err != nil {
 				return err
 			}
 		}
@@ -351,42 +456,43 @@ func (s *Selector[T]) buildHaving() error {
 	return nil
 }
 
-func (s *Selector[T]) addArgs(args ...any) {
-	if s.Args == nil {
-		s.Args = make([]any, 0, 8)
-	}
-	s.Args = append(s.Args, args...)
-}
-
-func (s *Selector[T]) buildPredicates(ps []Predicate) error {
-	p := ps[0]
-	for i := 1; i < len(ps); i++ {
-		p = p.And(ps[i])
-	}
-	return s.buildExpression(p)
-}
-
+// buildOrderBy 优先按照字段名解析，找不到的时候再看是不是 SELECT 列表中的别名
+// 例如 Select(Count("Id").As("cnt")).OrderBy(Desc("cnt"))
 func (s *Selector[T]) buildOrderBy() error {
-	if len(s.orderBy) > 0 {
-		s.sb.WriteString(" ORDER BY ")
-
-		for i, by := range s.orderBy {
-			if i > 0 {
-				s.sb.WriteByte(',')
+	return s.builder.buildOrderBy(s.orderBy, func(col string) error {
+		if _, ok := s.m.FieldMap[col]; ok {
+			return s.buildColumn(col)
+		}
+		for _, c := range s.columns {
+			if aliasOf(c) == col {
+				s.quote(col)
+				return nil
 			}
-
-			s.sb.WriteByte('`')
-			s.sb.WriteString(by.col)
-			s.sb.WriteByte('`')
-			s.sb.WriteString(" " + by.order)
 		}
-	}
-	return nil
+		return err2.NewErrUnknownColumn(col)
+	})
 }
 
 type OrderBy struct {
-	col   string
+	// col 字段名，或者 SELECT 列表中的别名
+	col string
+	// expr 不为空的时候按照表达式排序，例如 Raw("FIELD(`id`,?,?)", 1, 2).Asc()
+	expr  Expression
 	order string
+	// nulls NULL 值的位置，FIRST 或者 LAST
+	nulls string
+}
+
+// NullsFirst NULL 值排在最前面，只有方言支持的时候才能使用
+func (o OrderBy) NullsFirst() OrderBy {
+	o.nulls = "FIRST"
+	return o
+}
+
+// NullsLast NULL 值排在最后面，只有方言支持的时候才能使用
+func (o OrderBy) NullsLast() OrderBy {
+	o.nulls = "LAST"
+	return o
 }
 
 func Asc(col string) OrderBy {
@@ -3,8 +3,11 @@ package go_orm
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
+	err2 "go-orm/internal/err"
+	"go-orm/internal/model"
 	"go-orm/internal/valuer"
 	"log"
 	"testing"
@@ -33,6 +36,34 @@ func TestSelector_Build(t *testing.T) {
 			},
 			wantErr: nil,
 		},
+		{
+			name: "for update",
+			s:    NewSelector[TestModel](db).Where(C("Id").EQ(1)).ForUpdate(),
+			want: &Query{
+				SQL:  "SELECT * FROM `test_model` WHERE `id` = ? FOR UPDATE;",
+				Args: []any{1},
+			},
+		},
+		{
+			name: "for update skip locked",
+			s:    NewSelector[TestModel](db).Where(C("Age").GT(18)).OrderBy(Asc("Id")).Limit(10).ForUpdate().SkipLocked(),
+			want: &Query{
+				SQL:  "SELECT * FROM `test_model` WHERE `age` > ? ORDER BY `id` ASC LIMIT ? FOR UPDATE SKIP LOCKED;",
+				Args: []any{18, int32(10)},
+			},
+		},
+		{
+			name: "for share nowait",
+			s:    NewSelector[TestModel](db).ForShare().NoWait(),
+			want: &Query{
+				SQL: "SELECT * FROM `test_model` FOR SHARE NOWAIT;",
+			},
+		},
+		{
+			name:    "skip locked without lock",
+			s:       NewSelector[TestModel](db).SkipLocked(),
+			wantErr: ErrLockOptionWithoutLock,
+		},
 		{
 			name: "not from",
 			s:    NewSelector[TestModel](db),
@@ -85,6 +116,341 @@ func TestSelector_Build(t *testing.T) {
 			},
 			wantErr: nil,
 		},
+		{
+			name: "compare",
+			s:    NewSelector[TestModel](db).Where(C("Id").NEQ(1), C("Age").GTE(18), C("Age").LTE(35)),
+			want: &Query{
+				SQL:  "SELECT * FROM `test_model` WHERE ((`id` != ?) AND (`age` >= ?)) AND (`age` <= ?);",
+				Args: []any{1, 18, 35},
+			},
+		},
+		{
+			name: "in",
+			s:    NewSelector[TestModel](db).Where(C("Id").In(1, 2, 3)),
+			want: &Query{
+				SQL:  "SELECT * FROM `test_model` WHERE `id` IN (?,?,?);",
+				Args: []any{1, 2, 3},
+			},
+		},
+		{
+			name: "not in",
+			s:    NewSelector[TestModel](db).Where(C("Id").NotIn(1, 2)),
+			want: &Query{
+				SQL:  "SELECT * FROM `test_model` WHERE `id` NOT IN (?,?);",
+				Args: []any{1, 2},
+			},
+		},
+		{
+			name: "empty in",
+			s:    NewSelector[TestModel](db).Where(C("Id").In(), C("Age").NotIn()),
+			want: &Query{
+				SQL: "SELECT * FROM `test_model` WHERE (1=0) AND (1=1);",
+			},
+		},
+		{
+			name: "like",
+			s:    NewSelector[TestModel](db).Where(C("FirstName").Like("li%").Or(C("FirstName").NotLike("%wang"))),
+			want: &Query{
+				SQL:  "SELECT * FROM `test_model` WHERE (`first_name` LIKE ?) OR (`first_name` NOT LIKE ?);",
+				Args: []any{"li%", "%wang"},
+			},
+		},
+		{
+			name: "between",
+			s:    NewSelector[TestModel](db).Where(C("Age").Between(18, 35)),
+			want: &Query{
+				SQL:  "SELECT * FROM `test_model` WHERE `age` BETWEEN ? AND ?;",
+				Args: []any{18, 35},
+			},
+		},
+		{
+			name: "is null",
+			s:    NewSelector[TestModel](db).Where(C("LastName").IsNull(), Not(C("FirstName").IsNotNull())),
+			want: &Query{
+				SQL: "SELECT * FROM `test_model` WHERE (`last_name` IS NULL) AND (NOT (`first_name` IS NOT NULL));",
+			},
+		},
+		{
+			name: "raw predicate",
+			s:    NewSelector[TestModel](db).Where(Raw("`age` > ? AND `age` < ?", 18, 35).AsPredicate(), C("Id").EQ(1)),
+			want: &Query{
+				SQL:  "SELECT * FROM `test_model` WHERE (`age` > ? AND `age` < ?) AND (`id` = ?);",
+				Args: []any{18, 35, 1},
+			},
+		},
+		{
+			name: "raw args order",
+			s: NewSelector[TestModel](db).Select(Raw("COUNT(DISTINCT `age` > ?)", 18)).
+				Where(C("Id").GT(1)).GroupBy(C("FirstName")).
+				Having(Raw("COUNT(*) > ?", 2).AsPredicate()).
+				OrderBy(Raw("FIELD(`id`,?,?)", 3, 4).Asc()),
+			want: &Query{
+				SQL: "SELECT COUNT(DISTINCT `age` > ?) FROM `test_model` WHERE `id` > ?" +
+					" GROUP BY `first_name` HAVING COUNT(*) > ? ORDER BY FIELD(`id`,?,?) ASC;",
+				Args: []any{18, 1, 2, 3, 4},
+			},
+		},
+		{
+			name: "math predicate",
+			s:    NewSelector[TestModel](db).Where(C("Age").Multi(C("Id")).GT(100), C("Age").Add(1).Multi(2).LT(C("Id"))),
+			want: &Query{
+				SQL:  "SELECT * FROM `test_model` WHERE (`age` * `id` > ?) AND ((`age` + ?) * ? < `id`);",
+				Args: []any{100, 1, 2},
+			},
+		},
+		{
+			name: "func predicate",
+			s:    NewSelector[TestModel](db).Where(Fn("LOWER", C("FirstName")).EQ("liu"), Fn("COALESCE", C("LastName"), "").NEQ("")),
+			want: &Query{
+				SQL:  "SELECT * FROM `test_model` WHERE (LOWER(`first_name`) = ?) AND (COALESCE(`last_name`,?) != ?);",
+				Args: []any{"liu", "", ""},
+			},
+		},
+		{
+			name:    "in unknown column",
+			s:       NewSelector[TestModel](db).Where(C("Invalid").In(1)),
+			wantErr: err2.NewErrUnknownColumn("Invalid"),
+		},
+		{
+			name:    "empty predicate",
+			s:       NewSelector[TestModel](db).Where(Predicate{}),
+			wantErr: ErrEmptyPredicate,
+		},
+		{
+			name:    "empty in unknown column",
+			s:       NewSelector[TestModel](db).Where(C("Nope").In()),
+			wantErr: err2.NewErrUnknownColumn("Nope"),
+		},
+		{
+			name:    "in without values",
+			s:       NewSelector[TestModel](db).Where(Predicate{left: C("Id"), op: opIN, right: Value{val: 1}}),
+			wantErr: err2.NewErrUnsupportedExpressionType(Value{val: 1}),
+		},
+		{
+			name:    "between without values",
+			s:       NewSelector[TestModel](db).Where(Predicate{left: C("Age"), op: opBETWEEN, right: Value{val: 1}}),
+			wantErr: err2.NewErrUnsupportedExpressionType(Value{val: 1}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.Build()
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+type Order struct {
+	Id        int
+	UsingCol1 string
+	UsingCol2 string
+}
+
+type OrderDetail struct {
+	OrderId   int
+	ItemId    int
+	UsingCol1 string
+	UsingCol2 string
+}
+
+type Item struct {
+	Id int
+}
+
+func TestSelector_Join(t *testing.T) {
+	db := memoryDB()
+	t1 := TableOf[Order]().As("t1")
+	t2 := TableOf[OrderDetail]().As("t2")
+	t3 := TableOf[Item]().As("t3")
+	tests := []struct {
+		name    string
+		s       QueryBuilder
+		want    *Query
+		wantErr error
+	}{
+		{
+			name: "table alias",
+			s:    NewSelector[Order](db).Select(t1.C("Id")).FromTable(t1).Where(t1.C("Id").EQ(1)),
+			want: &Query{
+				SQL:  "SELECT `t1`.`id` FROM `order` AS `t1` WHERE `t1`.`id` = ?;",
+				Args: []any{1},
+			},
+		},
+		{
+			name: "join on",
+			s: NewSelector[Order](db).Select(t1.C("Id"), t2.C("ItemId").As("item")).
+				FromTable(t1.Join(t2).On(t1.C("Id").EQ(t2.C("OrderId")))).Where(t2.C("ItemId").GT(10)),
+			want: &Query{
+				SQL: "SELECT `t1`.`id`,`t2`.`item_id` as `item` FROM `order` AS `t1` JOIN `order_detail` AS `t2`" +
+					" ON `t1`.`id` = `t2`.`order_id` WHERE `t2`.`item_id` > ?;",
+				Args: []any{10},
+			},
+		},
+		{
+			name: "join without alias",
+			s: func() QueryBuilder {
+				o := TableOf[Order]()
+				i := TableOf[Item]()
+				return NewSelector[Order](db).Select(o.C("Id")).FromTable(o.Join(i).On(o.C("Id").EQ(i.C("Id"))))
+			}(),
+			want: &Query{
+				SQL: "SELECT `order`.`id` FROM `order` JOIN `item` ON `order`.`id` = `item`.`id`;",
+			},
+		},
+		{
+			name: "left join using",
+			s:    NewSelector[Order](db).FromTable(t1.LeftJoin(t2).Using("UsingCol1", "UsingCol2")),
+			want: &Query{
+				SQL: "SELECT * FROM `order` AS `t1` LEFT JOIN `order_detail` AS `t2` USING (`using_col1`,`using_col2`);",
+			},
+		},
+		{
+			name:    "join using unknown column",
+			s:       NewSelector[Order](db).FromTable(t1.Join(t3).Using("UsingCol1")),
+			wantErr: err2.NewErrUnknownColumn("UsingCol1"),
+		},
+		{
+			name: "join using join",
+			s: NewSelector[Order](db).FromTable(t1.Join(t2.Join(t3).On(t2.C("ItemId").EQ(t3.C("Id")))).
+				Using("UsingCol1")),
+			wantErr: err2.NewErrUnsupportedTable(t2.Join(t3).On(t2.C("ItemId").EQ(t3.C("Id")))),
+		},
+		{
+			name: "multi join",
+			s: NewSelector[Order](db).FromTable(t1.Join(t2).On(t1.C("Id").EQ(t2.C("OrderId"))).
+				RightJoin(t3).On(t2.C("ItemId").EQ(t3.C("Id")))),
+			want: &Query{
+				SQL: "SELECT * FROM `order` AS `t1` JOIN `order_detail` AS `t2` ON `t1`.`id` = `t2`.`order_id`" +
+					" RIGHT JOIN `item` AS `t3` ON `t2`.`item_id` = `t3`.`id`;",
+			},
+		},
+		{
+			name: "join right join",
+			s:    NewSelector[Order](db).FromTable(t1.Join(t2.Join(t3).On(t2.C("ItemId").EQ(t3.C("Id")))).On(t1.C("Id").EQ(t2.C("OrderId")))),
+			want: &Query{
+				SQL: "SELECT * FROM `order` AS `t1` JOIN (`order_detail` AS `t2` JOIN `item` AS `t3` ON `t2`.`item_id` = `t3`.`id`)" +
+					" ON `t1`.`id` = `t2`.`order_id`;",
+			},
+		},
+		{
+			name: "aggregate on joined table",
+			s: NewSelector[Order](db).Select(t1.C("Id"), Fn("SUM", t2.C("ItemId")).As("total")).
+				FromTable(t1.Join(t2).On(t1.C("Id").EQ(t2.C("OrderId")))).GroupBy(t1.C("Id")),
+			want: &Query{
+				SQL: "SELECT `t1`.`id`,SUM(`t2`.`item_id`) as `total` FROM `order` AS `t1` JOIN `order_detail` AS `t2`" +
+					" ON `t1`.`id` = `t2`.`order_id` GROUP BY `t1`.`id`;",
+			},
+		},
+		{
+			name:    "unknown column of joined table",
+			s:       NewSelector[Order](db).FromTable(t1.Join(t2).On(t1.C("Id").EQ(t2.C("Invalid")))),
+			wantErr: err2.NewErrUnknownColumn("Invalid"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.Build()
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSelector_Subquery(t *testing.T) {
+	db := memoryDB()
+	tests := []struct {
+		name    string
+		s       QueryBuilder
+		want    *Query
+		wantErr error
+	}{
+		{
+			name: "from",
+			s: func() QueryBuilder {
+				sub := NewSelector[OrderDetail](db).Select(C("OrderId"), Count("ItemId").As("cnt")).
+					Where(C("ItemId").GT(10)).GroupBy(C("OrderId")).AsSubquery("sub")
+				return NewSelector[OrderDetail](db).Select(sub.C("OrderId"), sub.C("cnt")).
+					FromTable(sub).Where(sub.C("cnt").GT(2))
+			}(),
+			want: &Query{
+				SQL: "SELECT `sub`.`order_id`,`sub`.`cnt` FROM (SELECT `order_id`,COUNT(`item_id`) as `cnt` FROM `order_detail`" +
+					" WHERE `item_id` > ? GROUP BY `order_id`) AS `sub` WHERE `sub`.`cnt` > ?;",
+				Args: []any{10, 2},
+			},
+		},
+		{
+			name:    "from without alias",
+			s:       NewSelector[Order](db).FromTable(NewSelector[Order](db).AsSubquery("")),
+			wantErr: ErrSubqueryWithoutAlias,
+		},
+		{
+			name: "join subquery",
+			s: func() QueryBuilder {
+				t1 := TableOf[Order]().As("t1")
+				sub := NewSelector[OrderDetail](db).Where(C("ItemId").EQ(3)).AsSubquery("sub")
+				return NewSelector[Order](db).Select(t1.C("Id"), sub.C("ItemId")).
+					FromTable(t1.Join(sub).On(t1.C("Id").EQ(sub.C("OrderId")))).Where(t1.C("Id").LT(100))
+			}(),
+			want: &Query{
+				SQL: "SELECT `t1`.`id`,`sub`.`item_id` FROM `order` AS `t1` JOIN (SELECT * FROM `order_detail` WHERE `item_id` = ?)" +
+					" AS `sub` ON `t1`.`id` = `sub`.`order_id` WHERE `t1`.`id` < ?;",
+				Args: []any{3, 100},
+			},
+		},
+		{
+			name: "in",
+			s: NewSelector[Order](db).Where(C("Id").GT(1), C("Id").In(
+				NewSelector[OrderDetail](db).Select(C("OrderId")).Where(C("ItemId").EQ(5)))),
+			want: &Query{
+				SQL:  "SELECT * FROM `order` WHERE (`id` > ?) AND (`id` IN (SELECT `order_id` FROM `order_detail` WHERE `item_id` = ?));",
+				Args: []any{1, 5},
+			},
+		},
+		{
+			name: "exists",
+			s: func() QueryBuilder {
+				t1 := TableOf[Order]().As("t1")
+				t2 := TableOf[OrderDetail]().As("t2")
+				return NewSelector[Order](db).FromTable(t1).Where(
+					Exists(NewSelector[OrderDetail](db).Select(Raw("1")).FromTable(t2).Where(t2.C("OrderId").EQ(t1.C("Id")))),
+					NotExists(NewSelector[Item](db).Where(C("Id").EQ(7))))
+			}(),
+			want: &Query{
+				SQL: "SELECT * FROM `order` AS `t1` WHERE (EXISTS (SELECT 1 FROM `order_detail` AS `t2` WHERE `t2`.`order_id` = `t1`.`id`))" +
+					" AND (NOT EXISTS (SELECT * FROM `item` WHERE `id` = ?));",
+				Args: []any{7},
+			},
+		},
+		{
+			name: "scalar",
+			s: func() QueryBuilder {
+				t1 := TableOf[Order]().As("t1")
+				t2 := TableOf[OrderDetail]().As("t2")
+				cnt := NewSelector[OrderDetail](db).Select(Count("*")).FromTable(t2).
+					Where(t2.C("OrderId").EQ(t1.C("Id")), t2.C("ItemId").GT(1)).AsSubquery("cnt")
+				return NewSelector[Order](db).Select(t1.C("Id"), cnt).FromTable(t1).Where(t1.C("Id").LT(9))
+			}(),
+			want: &Query{
+				SQL: "SELECT `t1`.`id`,(SELECT COUNT(*) FROM `order_detail` AS `t2` WHERE (`t2`.`order_id` = `t1`.`id`) AND (`t2`.`item_id` > ?))" +
+					" as `cnt` FROM `order` AS `t1` WHERE `t1`.`id` < ?;",
+				Args: []any{1, 9},
+			},
+		},
+		{
+			name: "unknown subquery column",
+			s: func() QueryBuilder {
+				sub := NewSelector[OrderDetail](db).Select(C("OrderId")).AsSubquery("sub")
+				return NewSelector[OrderDetail](db).Select(sub.C("ItemId")).FromTable(sub)
+			}(),
+			wantErr: err2.NewErrUnknownColumn("ItemId"),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -154,8 +520,174 @@ func TestSelector_Get(t *testing.T) {
 	}
 }
 
-func memoryDB() *DB {
-	orm, err := Open("mysql", "root:root@tcp(localhost:3306)/test?charset=utf8mb4")
+func TestSelector_GetMulti(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	tests := []struct {
+		name     string
+		query    string
+		mockErr  error
+		mockRows *sqlmock.Rows
+		wantErr  error
+		wantVal  []*TestModel
+	}{
+		{
+			name:    "query error",
+			query:   "SELECT .*",
+			mockErr: errors.New("query error"),
+			wantErr: errors.New("query error"),
+		},
+		{
+			name:  "no rows",
+			query: "SELECT .*",
+			mockRows: func() *sqlmock.Rows {
+				return sqlmock.NewRows([]string{"id", "first_name", "age", "last_name"})
+			}(),
+			wantVal: []*TestModel{},
+		},
+		{
+			name:  "multi rows",
+			query: "SELECT .*",
+			mockRows: func() *sqlmock.Rows {
+				res := sqlmock.NewRows([]string{"id", "first_name", "age", "last_name"})
+				res.AddRow([]byte("1"), []byte("Liu"), []byte("18"), []byte("Quan"))
+				res.AddRow([]byte("2"), []byte("Wang"), []byte("30"), []byte("Liang"))
+				return res
+			}(),
+			wantVal: []*TestModel{
+				{
+					Id:        1,
+					FirstName: "Liu",
+					Age:       18,
+					LastName:  &sql.NullString{String: "Quan", Valid: true},
+				},
+				{
+					Id:        2,
+					FirstName: "Wang",
+					Age:       30,
+					LastName:  &sql.NullString{String: "Liang", Valid: true},
+				},
+			},
+		},
+	}
+
+	openDB, err := OpenDB(db, DBUseReflectValuer())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exp := mock.ExpectQuery(tt.query)
+			if tt.mockErr != nil {
+				exp.WillReturnError(tt.mockErr)
+			} else {
+				exp.WillReturnRows(tt.mockRows)
+			}
+
+			res, err := NewSelector[TestModel](openDB).GetMulti(context.Background())
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.wantVal, res)
+		})
+	}
+}
+
+func TestSelector_GetMulti_ctxCancel(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	openDB, err := OpenDB(db, DBUseReflectValuer())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "first_name", "age", "last_name"})
+	rows.AddRow([]byte("1"), []byte("Liu"), []byte("18"), []byte("Quan"))
+	rows.AddRow([]byte("2"), []byte("Wang"), []byte("30"), []byte("Liang"))
+	rows.AddRow([]byte("3"), []byte("Zhang"), []byte("40"), []byte("Li"))
+	mock.ExpectQuery("SELECT .*").WillReturnRows(rows)
+
+	// 映射完第一行之后取消，后面的行不应该再被映射
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	scanned := 0
+	creator := openDB.valCreator
+	openDB.valCreator = func(t any, m *model.Model) valuer.Value {
+		scanned++
+		if scanned == 1 {
+			cancel()
+		}
+		return creator(t, m)
+	}
+	_, err = NewSelector[TestModel](openDB).GetMulti(ctx)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 1, scanned)
+}
+
+func TestSelector_Iter(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	openDB, err := OpenDB(db, DBUseReflectValuer())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mock.ExpectQuery("SELECT .*").WillReturnError(errors.New("query error"))
+	_, err = NewSelector[TestModel](openDB).Iter(context.Background())
+	assert.Equal(t, errors.New("query error"), err)
+
+	rows := sqlmock.NewRows([]string{"id", "first_name", "age", "last_name"})
+	rows.AddRow([]byte("1"), []byte("Liu"), []byte("18"), []byte("Quan"))
+	rows.AddRow([]byte("2"), []byte("Wang"), []byte("30"), []byte("Liang"))
+	mock.ExpectQuery("SELECT .*").WillReturnRows(rows)
+
+	it, err := NewSelector[TestModel](openDB).Iter(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer it.Close()
+
+	res := make([]*TestModel, 0, 2)
+	for it.Next() {
+		tm, err := it.Scan()
+		if err != nil {
+			t.Fatal(err)
+		}
+		res = append(res, tm)
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []*TestModel{
+		{
+			Id:        1,
+			FirstName: "Liu",
+			Age:       18,
+			LastName:  &sql.NullString{String: "Quan", Valid: true},
+		},
+		{
+			Id:        2,
+			FirstName: "Wang",
+			Age:       30,
+			LastName:  &sql.NullString{String: "Liang", Valid: true},
+		},
+	}, res)
+}
+
+func memoryDB(opts ...DBOption) *DB {
+	orm, err := Open("mysql", "root:root@tcp(localhost:3306)/test?charset=utf8mb4", opts...)
 	if err != nil {
 		panic(err)
 	}
@@ -202,10 +734,11 @@ func BenchmarkQuerier_Get(b *testing.B) {
 func TestSelector_Select(t *testing.T) {
 	db := memoryDB()
 	type testCase[T any] struct {
-		name string
-		s    QueryBuilder
-		cols []string
-		want *Query
+		name    string
+		s       QueryBuilder
+		cols    []string
+		want    *Query
+		wantErr error
 	}
 	tests := []testCase[TestModel]{
 		{
@@ -251,6 +784,37 @@ func TestSelector_Select(t *testing.T) {
 				SQL: "SELECT AVG(`age`) as `a`,MIN(`first_name`) as `f` FROM `test_model`;",
 			},
 		},
+		{
+			name: "math and func",
+			s:    NewSelector[TestModel](db).Select(C("Age").Add(1).As("next_age"), Fn("LOWER", C("FirstName")).As("name"), Fn("NOW")),
+			want: &Query{
+				SQL:  "SELECT `age` + ? as `next_age`,LOWER(`first_name`) as `name`,NOW() FROM `test_model`;",
+				Args: []any{1},
+			},
+		},
+		{
+			name: "count sum",
+			s:    NewSelector[TestModel](db).Select(Count("*"), Count("Id").As("cnt"), CountDistinct("Age"), Sum("Age").As("total")),
+			want: &Query{
+				SQL: "SELECT COUNT(*),COUNT(`id`) as `cnt`,COUNT(DISTINCT `age`),SUM(`age`) as `total` FROM `test_model`;",
+			},
+		},
+		{
+			name: "distinct",
+			s:    NewSelector[TestModel](db).Distinct().Select(C("FirstName")),
+			want: &Query{
+				SQL: "SELECT DISTINCT `first_name` FROM `test_model`;",
+			},
+		},
+		{
+			name: "having aggregate",
+			s: NewSelector[TestModel](db).Select(C("Age"), Count("Id")).GroupBy(C("Age")).
+				Having(Count("Id").GT(5), Avg("Id").LTE(C("Age"))),
+			want: &Query{
+				SQL:  "SELECT `age`,COUNT(`id`) FROM `test_model` GROUP BY `age` HAVING (COUNT(`id`) > ?) AND (AVG(`id`) <= `age`);",
+				Args: []any{5},
+			},
+		},
 		{
 			name: "group by",
 			s:    NewSelector[TestModel](db).Select(Avg("Age").As("a"), Min("FirstName").As("f")).GroupBy(C("Age"), C("FirstName")),
@@ -276,17 +840,44 @@ func TestSelector_Select(t *testing.T) {
 		},
 		{
 			name: "order by ",
-			s:    NewSelector[TestModel](db).Select(C("Age")).OrderBy(Asc("age")),
+			s:    NewSelector[TestModel](db).Select(C("Age")).OrderBy(Asc("Age")),
 			want: &Query{
 				SQL: "SELECT `age` FROM `test_model` ORDER BY `age` ASC;",
 			},
 		},
+		{
+			name:    "order by unknown column",
+			s:       NewSelector[TestModel](db).OrderBy(Asc("age")),
+			wantErr: err2.NewErrUnknownColumn("age"),
+		},
+		{
+			name: "order by alias",
+			s: NewSelector[TestModel](db).Select(C("Age"), Count("Id").As("cnt")).GroupBy(C("Age")).
+				OrderBy(Desc("cnt"), Asc("Age")),
+			want: &Query{
+				SQL: "SELECT `age`,COUNT(`id`) as `cnt` FROM `test_model` GROUP BY `age` ORDER BY `cnt` DESC,`age` ASC;",
+			},
+		},
+		{
+			name: "order by expression",
+			s: NewSelector[TestModel](db).Select(C("Age")).GroupBy(C("Age")).
+				OrderBy(Count("Id").Desc(), C("Age").Add(1).Asc(), Fn("LOWER", C("FirstName")).Desc()),
+			want: &Query{
+				SQL:  "SELECT `age` FROM `test_model` GROUP BY `age` ORDER BY COUNT(`id`) DESC,`age` + ? ASC,LOWER(`first_name`) DESC;",
+				Args: []any{1},
+			},
+		},
+		{
+			name:    "order by nulls",
+			s:       NewSelector[TestModel](db).OrderBy(Asc("LastName").NullsLast()),
+			wantErr: err2.NewErrUnsupportedClause("NULLS LAST"),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			build, err := tt.s.Build()
+			assert.Equal(t, tt.wantErr, err)
 			if err != nil {
-				t.Fatal(err)
 				return
 			}
 			assert.Equal(t, tt.want, build)
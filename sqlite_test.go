@@ -0,0 +1,175 @@
+package go_orm
+
+import (
+	"context"
+	"database/sql"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	err2 "go-orm/internal/err"
+	"path/filepath"
+	"testing"
+)
+
+// sqliteDB 在临时目录里创建一个 SQLite 文件数据库
+// 不依赖外部的数据库服务，可以直接在 CI 里跑端到端测试
+func sqliteDB(t *testing.T) *DB {
+	db, err := Open("sqlite3", "file:"+filepath.Join(t.TempDir(), "orm.db"), DBWithDialect(SQLite))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.db.Close()
+	})
+
+	_, err = db.db.Exec("CREATE TABLE `test_model`(" +
+		"`id` INTEGER PRIMARY KEY," +
+		"`first_name` TEXT NOT NULL," +
+		"`age` INTEGER NOT NULL," +
+		"`last_name` TEXT)")
+	require.NoError(t, err)
+	return db
+}
+
+func TestSQLiteDialect_Build(t *testing.T) {
+	db := memoryDB(DBWithDialect(SQLite))
+	tests := []struct {
+		name    string
+		b       QueryBuilder
+		want    *Query
+		wantErr error
+	}{
+		{
+			name: "insert",
+			b:    NewInserter[TestModel](db).Columns("Id", "FirstName").Values(&TestModel{Id: 1, FirstName: "liu"}),
+			want: &Query{
+				SQL:  `INSERT INTO "test_model"("id","first_name")VALUES(?,?);`,
+				Args: []any{int64(1), "liu"},
+			},
+		},
+		{
+			name: "select",
+			b:    NewSelector[TestModel](db).Select(C("FirstName").As("name")).Where(C("Id").EQ(1)).Limit(1),
+			want: &Query{
+				SQL:  `SELECT "first_name" as "name" FROM "test_model" WHERE "id" = ? LIMIT ?;`,
+				Args: []any{1, int32(1)},
+			},
+		},
+		{
+			name:    "for update",
+			b:       NewSelector[TestModel](db).Where(C("Id").EQ(1)).ForUpdate(),
+			wantErr: err2.NewErrUnsupportedClause("FOR UPDATE"),
+		},
+		{
+			name:    "for share",
+			b:       NewSelector[TestModel](db).ForShare().NoWait(),
+			wantErr: err2.NewErrUnsupportedClause("FOR SHARE"),
+		},
+		{
+			name: "insert or ignore",
+			b:    NewInserter[TestModel](db).Columns("Id").Values(&TestModel{Id: 1}).Ignore(),
+			want: &Query{
+				SQL:  `INSERT OR IGNORE INTO "test_model"("id")VALUES(?);`,
+				Args: []any{int64(1)},
+			},
+		},
+		{
+			name: "insert or replace",
+			b:    NewInserter[TestModel](db).Columns("Id").Values(&TestModel{Id: 1}).Replace(),
+			want: &Query{
+				SQL:  `INSERT OR REPLACE INTO "test_model"("id")VALUES(?);`,
+				Args: []any{int64(1)},
+			},
+		},
+		{
+			name: "upsert",
+			b: NewInserter[TestModel](db).Columns("Id", "FirstName").Values(&TestModel{Id: 1, FirstName: "liu"}).
+				OnDuplicateKey().ConflictColumns("Id").Update(C("FirstName"), Assign("Age", 18)),
+			want: &Query{
+				SQL: `INSERT INTO "test_model"("id","first_name")VALUES(?,?)` +
+					` ON CONFLICT ("id") DO UPDATE SET "first_name"=excluded."first_name","age"=?;`,
+				Args: []any{int64(1), "liu", 18},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.b.Build()
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSQLite_e2e(t *testing.T) {
+	db := sqliteDB(t)
+	ctx := context.Background()
+
+	res := NewInserter[TestModel](db).Values(
+		&TestModel{Id: 1, FirstName: "liu", Age: 18, LastName: &sql.NullString{String: "quan", Valid: true}},
+		&TestModel{Id: 2, FirstName: "wang", Age: 30},
+	).Exec(ctx)
+	affected, err := res.RowsAffected()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), affected)
+
+	// 主键冲突的时候忽略
+	res = NewInserter[TestModel](db).Values(&TestModel{Id: 1, FirstName: "ignored", Age: 1}).Ignore().Exec(ctx)
+	affected, err = res.RowsAffected()
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), affected)
+
+	// 主键冲突的时候更新
+	res = NewInserter[TestModel](db).Values(&TestModel{Id: 2, FirstName: "zhang", Age: 31}).
+		OnDuplicateKey().ConflictColumns("Id").Update(C("FirstName"), Assign("Age", 40)).Exec(ctx)
+	require.NoError(t, res.(Result).Err())
+
+	got, err := NewSelector[TestModel](db).Where(C("Id").EQ(2)).Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &TestModel{Id: 2, FirstName: "zhang", Age: 40}, got)
+
+	// 整行替换
+	res = NewInserter[TestModel](db).Values(&TestModel{Id: 2, FirstName: "li", Age: 20}).Replace().Exec(ctx)
+	require.NoError(t, res.(Result).Err())
+
+	// 回传自增主键
+	vals := []*TestModel{{FirstName: "zhao", Age: 50}}
+	res = NewInserter[TestModel](db).Columns("FirstName", "Age").Values(vals...).Returning("Id").Exec(ctx)
+	require.NoError(t, res.(Result).Err())
+	assert.Equal(t, int64(3), vals[0].Id)
+
+	res = NewUpdater[TestModel](db).Set(Assign("Age", 19)).Where(C("Id").EQ(1)).Exec(ctx)
+	affected, err = res.RowsAffected()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), affected)
+
+	res = NewDeleter[TestModel](db).Where(C("Id").EQ(3)).Exec(ctx)
+	affected, err = res.RowsAffected()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), affected)
+
+	all, err := NewSelector[TestModel](db).GetMulti(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []*TestModel{
+		{Id: 1, FirstName: "liu", Age: 19, LastName: &sql.NullString{String: "quan", Valid: true}},
+		{Id: 2, FirstName: "li", Age: 20},
+	}, all)
+
+	_, err = NewSelector[TestModel](db).Where(C("Id").EQ(3)).Get(ctx)
+	assert.Equal(t, ErrNoRows, err)
+}
+
+func TestSQLite_tx(t *testing.T) {
+	db := sqliteDB(t)
+	ctx := context.Background()
+
+	tx, err := db.Begin(ctx, nil)
+	require.NoError(t, err)
+	res := NewInserter[TestModel](tx).Values(&TestModel{Id: 1, FirstName: "liu", Age: 18}).Exec(ctx)
+	require.NoError(t, res.(Result).Err())
+	require.NoError(t, tx.Rollback())
+
+	_, err = NewSelector[TestModel](db).Where(C("Id").EQ(1)).Get(ctx)
+	assert.Equal(t, ErrNoRows, err)
+}
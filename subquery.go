@@ -0,0 +1,67 @@
+package go_orm
+
+// SubqueryBuilder 可以作为子查询的查询，例如 *Selector
+type SubqueryBuilder interface {
+	subquery() Subquery
+}
+
+// subquerySource 子查询的实际构造者
+type subquerySource interface {
+	buildSubquery(offset int) (*Query, error)
+	subqueryColumn(name string) (string, bool)
+	// standalone 作为 UNION 等集合操作的一部分的时候能否省略括号
+	standalone() bool
+}
+
+// Subquery 子查询
+// 可以用在 FromTable、IN、EXISTS 以及 SELECT 列表里面
+type Subquery struct {
+	s     subquerySource
+	alias string
+}
+
+func (s Subquery) subquery() Subquery {
+	return s
+}
+
+func (s Subquery) tableAlias() string {
+	return s.alias
+}
+
+func (s Subquery) expr() {}
+
+func (s Subquery) selectable() {}
+
+// C 引用子查询的列，name 可以是子查询中的别名或者字段名
+func (s Subquery) C(name string) Column {
+	return Column{
+		table: s,
+		name:  name,
+	}
+}
+
+func (s Subquery) Join(right TableReference) *JoinBuilder {
+	return newJoinBuilder(s, right, "JOIN")
+}
+
+func (s Subquery) LeftJoin(right TableReference) *JoinBuilder {
+	return newJoinBuilder(s, right, "LEFT JOIN")
+}
+
+func (s Subquery) RightJoin(right TableReference) *JoinBuilder {
+	return newJoinBuilder(s, right, "RIGHT JOIN")
+}
+
+func Exists(sub SubqueryBuilder) Predicate {
+	return Predicate{
+		op:    opExists,
+		right: sub.subquery(),
+	}
+}
+
+func NotExists(sub SubqueryBuilder) Predicate {
+	return Predicate{
+		op:    opNotExists,
+		right: sub.subquery(),
+	}
+}
@@ -0,0 +1,117 @@
+package go_orm
+
+// TableReference 代表 FROM 后面的部分
+// 目前有普通的表 Table、Join、子查询 Subquery 和 CTE
+type TableReference interface {
+	tableAlias() string
+}
+
+// Table 普通的表
+type Table struct {
+	entity any
+	// name 直接指定的表名，例如 db.table_name
+	name  string
+	alias string
+}
+
+// TableOf 使用 T 的元数据作为表
+func TableOf[T any]() Table {
+	return Table{
+		entity: new(T),
+	}
+}
+
+// TableNamed 直接指定表名，支持 db.table_name 的写法
+// 没有 entity 的时候，列按照 Selector 的 T 来解析
+func TableNamed(name string) Table {
+	return Table{
+		name: name,
+	}
+}
+
+func (t Table) tableAlias() string {
+	return t.alias
+}
+
+func (t Table) As(alias string) Table {
+	t.alias = alias
+	return t
+}
+
+// C 使用该表的列，输出为 `alias`.`col`，没有别名的时候用表名限定
+func (t Table) C(name string) Column {
+	return Column{
+		table: t,
+		name:  name,
+	}
+}
+
+func (t Table) Join(right TableReference) *JoinBuilder {
+	return newJoinBuilder(t, right, "JOIN")
+}
+
+func (t Table) LeftJoin(right TableReference) *JoinBuilder {
+	return newJoinBuilder(t, right, "LEFT JOIN")
+}
+
+func (t Table) RightJoin(right TableReference) *JoinBuilder {
+	return newJoinBuilder(t, right, "RIGHT JOIN")
+}
+
+// Join 由 JoinBuilder 的 On 或者 Using 构造
+type Join struct {
+	left  TableReference
+	right TableReference
+	typ   string
+	on    []Predicate
+	using []string
+}
+
+func (j Join) tableAlias() string {
+	return ""
+}
+
+func (j Join) Join(right TableReference) *JoinBuilder {
+	return newJoinBuilder(j, right, "JOIN")
+}
+
+func (j Join) LeftJoin(right TableReference) *JoinBuilder {
+	return newJoinBuilder(j, right, "LEFT JOIN")
+}
+
+func (j Join) RightJoin(right TableReference) *JoinBuilder {
+	return newJoinBuilder(j, right, "RIGHT JOIN")
+}
+
+type JoinBuilder struct {
+	left  TableReference
+	right TableReference
+	typ   string
+}
+
+func newJoinBuilder(left, right TableReference, typ string) *JoinBuilder {
+	return &JoinBuilder{
+		left:  left,
+		right: right,
+		typ:   typ,
+	}
+}
+
+func (j *JoinBuilder) On(ps ...Predicate) Join {
+	return Join{
+		left:  j.left,
+		right: j.right,
+		typ:   j.typ,
+		on:    ps,
+	}
+}
+
+// Using 使用两张表同名的列作为连接条件，cols 为字段名
+func (j *JoinBuilder) Using(cols ...string) Join {
+	return Join{
+		left:  j.left,
+		right: j.right,
+		typ:   j.typ,
+		using: cols,
+	}
+}
@@ -25,17 +25,19 @@ func (t *Tx) getCore() core {
 }
 
 func (t *Tx) queryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
-	return t.tx.QueryContext(ctx, query, args)
+	return t.tx.QueryContext(ctx, query, args...)
 }
 
-func (t *Tx) exec(query string, args ...any) (sql.Result, error) {
-	return t.tx.Exec(query, args)
+func (t *Tx) execContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return t.tx.ExecContext(ctx, query, args...)
 }
 
+// Session 代表一个可以执行查询的上下文，DB 或者 Tx
+// args 必须原样展开传给 database/sql
 type Session interface {
 	getCore() core
 	queryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
-	exec(query string, args ...any) (sql.Result, error)
+	execContext(ctx context.Context, query string, args ...any) (sql.Result, error)
 }
 
 type core struct {
@@ -0,0 +1,81 @@
+package go_orm
+
+import (
+	"context"
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTx_queryContext(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+
+	db, err := OpenDB(mockDB, DBUseReflectValuer())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "first_name", "age", "last_name"})
+	rows.AddRow([]byte("1"), []byte("Liu"), []byte("18"), []byte("Quan"))
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT \\* FROM `test_model` WHERE `id` = \\?;").
+		WithArgs(1).
+		WillReturnRows(rows)
+	mock.ExpectCommit()
+
+	tx, err := db.Begin(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := NewSelector[TestModel](tx).Where(C("Id").EQ(1)).GetMulti(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, tx.Commit())
+	assert.Equal(t, []*TestModel{
+		{
+			Id:        1,
+			FirstName: "Liu",
+			Age:       18,
+			LastName:  &sql.NullString{String: "Quan", Valid: true},
+		},
+	}, res)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTx_exec(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+
+	db, err := OpenDB(mockDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `test_model` SET `age`=\\? WHERE `id` = \\?;").
+		WithArgs(30, 12).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectRollback()
+
+	tx, err := db.Begin(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := NewUpdater[TestModel](tx).Set(Assign("Age", 30)).Where(C("Id").EQ(12)).Exec(context.Background())
+	affected, err := res.RowsAffected()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, int64(1), affected)
+	assert.NoError(t, tx.Rollback())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
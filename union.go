@@ -0,0 +1,254 @@
+package go_orm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	err2 "go-orm/internal/err"
+)
+
+type setOperator string
+
+const (
+	setOpUnion     setOperator = "UNION"
+	setOpUnionAll  setOperator = "UNION ALL"
+	setOpIntersect setOperator = "INTERSECT"
+	setOpExcept    setOperator = "EXCEPT"
+)
+
+type setPart struct {
+	op setOperator
+	s  subquerySource
+}
+
+// SetQuery 多个查询的集合操作，例如 UNION、UNION ALL
+// 各个查询的列需要兼容，结果按照第一个查询的 T 来映射
+// 多个集合操作按照数据库的优先级计算，需要改变顺序的时候可以把 SetQuery 作为其中一个查询
+type SetQuery[T any] struct {
+	builder
+	first   subquerySource
+	parts   []setPart
+	orderBy []OrderBy
+	limit   int32
+	offset  int32
+
+	sess Session
+}
+
+func newSetQuery[T any](s *Selector[T], op setOperator, other SubqueryBuilder) *SetQuery[T] {
+	q := &SetQuery[T]{
+		builder: builder{
+			core: s.core,
+		},
+		first: s,
+		sess:  s.sess,
+	}
+	return q.add(op, other)
+}
+
+// Union 对应 UNION，会去掉重复的行
+func (s *Selector[T]) Union(other SubqueryBuilder) *SetQuery[T] {
+	return newSetQuery[T](s, setOpUnion, other)
+}
+
+// UnionAll 对应 UNION ALL，保留重复的行
+func (s *Selector[T]) UnionAll(other SubqueryBuilder) *SetQuery[T] {
+	return newSetQuery[T](s, setOpUnionAll, other)
+}
+
+func (s *Selector[T]) Intersect(other SubqueryBuilder) *SetQuery[T] {
+	return newSetQuery[T](s, setOpIntersect, other)
+}
+
+func (s *Selector[T]) Except(other SubqueryBuilder) *SetQuery[T] {
+	return newSetQuery[T](s, setOpExcept, other)
+}
+
+func (q *SetQuery[T]) add(op setOperator, other SubqueryBuilder) *SetQuery[T] {
+	q.parts = append(q.parts, setPart{
+		op: op,
+		s:  other.subquery().s,
+	})
+	return q
+}
+
+func (q *SetQuery[T]) Union(other SubqueryBuilder) *SetQuery[T] {
+	return q.add(setOpUnion, other)
+}
+
+func (q *SetQuery[T]) UnionAll(other SubqueryBuilder) *SetQuery[T] {
+	return q.add(setOpUnionAll, other)
+}
+
+func (q *SetQuery[T]) Intersect(other SubqueryBuilder) *SetQuery[T] {
+	return q.add(setOpIntersect, other)
+}
+
+func (q *SetQuery[T]) Except(other SubqueryBuilder) *SetQuery[T] {
+	return q.add(setOpExcept, other)
+}
+
+// OrderBy 对整个结果排序，列按照第一个查询的别名或者字段名解析
+func (q *SetQuery[T]) OrderBy(ps ...OrderBy) *SetQuery[T] {
+	q.orderBy = ps
+	return q
+}
+
+func (q *SetQuery[T]) Limit(l int32) *SetQuery[T] {
+	q.limit = l
+	return q
+}
+
+func (q *SetQuery[T]) Offset(o int32) *SetQuery[T] {
+	q.offset = o
+	return q
+}
+
+func (q *SetQuery[T]) Build() (*Query, error) {
+	q.argOffset = 0
+	if err := q.build(); err != nil {
+		return nil, err
+	}
+	q.sb.WriteByte(';')
+	return &Query{
+		SQL:  q.sb.String(),
+		Args: q.args,
+	}, nil
+}
+
+func (q *SetQuery[T]) buildSubquery(offset int) (*Query, error) {
+	q.argOffset = offset
+	if err := q.build(); err != nil {
+		return nil, err
+	}
+	return &Query{
+		SQL:  q.sb.String(),
+		Args: q.args,
+	}, nil
+}
+
+func (q *SetQuery[T]) subqueryColumn(name string) (string, bool) {
+	return q.first.subqueryColumn(name)
+}
+
+// standalone 作为集合操作的一部分的时候总是需要括号，保证计算顺序
+func (q *SetQuery[T]) standalone() bool {
+	return false
+}
+
+func (q *SetQuery[T]) AsSubquery(alias string) Subquery {
+	return Subquery{
+		s:     q,
+		alias: alias,
+	}
+}
+
+func (q *SetQuery[T]) subquery() Subquery {
+	return q.AsSubquery("")
+}
+
+func (q *SetQuery[T]) build() error {
+	var err error
+	q.reset()
+	q.m, err = q.r.Get(new(T))
+	if err != nil {
+		return err
+	}
+
+	if err = q.buildPart(q.first); err != nil {
+		return err
+	}
+	for _, p := range q.parts {
+		if !q.core.dialect.supportSetOperator(p.op) {
+			return err2.NewErrUnsupportedClause(string(p.op))
+		}
+		q.sb.WriteByte(' ')
+		q.sb.WriteString(string(p.op))
+		q.sb.WriteByte(' ')
+		if err = q.buildPart(p.s); err != nil {
+			return err
+		}
+	}
+
+	if err = q.buildOrderBy(); err != nil {
+		return err
+	}
+
+	if q.limit > 0 {
+		q.sb.WriteString(" LIMIT ")
+		q.parameter(q.limit)
+	}
+
+	if q.offset > 0 {
+		q.sb.WriteString(" OFFSET ")
+		q.parameter(q.offset)
+	}
+	return nil
+}
+
+// buildPart 构造其中一个查询
+// 只有带了 ORDER BY 或者 LIMIT 的查询才需要括号，具体写法由方言决定
+func (q *SetQuery[T]) buildPart(s subquerySource) error {
+	sub, err := s.buildSubquery(q.argOffset + len(q.args))
+	if err != nil {
+		return err
+	}
+	if s.standalone() {
+		q.sb.WriteString(sub.SQL)
+	} else {
+		q.core.dialect.buildSetPart(&q.builder, sub.SQL)
+	}
+	q.addArgs(sub.Args...)
+	return nil
+}
+
+func (q *SetQuery[T]) buildOrderBy() error {
+	return q.builder.buildOrderBy(q.orderBy, func(col string) error {
+		name, ok := q.first.subqueryColumn(col)
+		if !ok {
+			return err2.NewErrUnknownColumn(col)
+		}
+		q.quote(name)
+		return nil
+	})
+}
+
+func (q *SetQuery[T]) Get(ctx context.Context) (*T, error) {
+	res := query(ctx, q.sess, q.core, &QueryContext{
+		Type:    "SELECT",
+		Builder: q,
+	}, func(rows *sql.Rows) (any, error) {
+		return scanOne[T](rows, q.valCreator, q.m)
+	})
+
+	if res.Err != nil {
+		return nil, res.Err
+	}
+
+	t, ok := res.Result.(*T)
+	if !ok {
+		return nil, errors.New("类型错误")
+	}
+
+	return t, nil
+}
+
+func (q *SetQuery[T]) GetMulti(ctx context.Context) ([]*T, error) {
+	res := query(ctx, q.sess, q.core, &QueryContext{
+		Type:    "SELECT",
+		Builder: q,
+	}, func(rows *sql.Rows) (any, error) {
+		return scanMulti[T](ctx, rows, q.valCreator, q.m)
+	})
+
+	if res.Err != nil {
+		return nil, res.Err
+	}
+
+	ts, ok := res.Result.([]*T)
+	if !ok {
+		return nil, errors.New("类型错误")
+	}
+
+	return ts, nil
+}
@@ -0,0 +1,186 @@
+package go_orm
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	err2 "go-orm/internal/err"
+	"testing"
+)
+
+type OrderArchive struct {
+	Id        int
+	UsingCol1 string
+	UsingCol2 string
+}
+
+func TestSetQuery_Build(t *testing.T) {
+	db := memoryDB()
+	tests := []struct {
+		name    string
+		s       QueryBuilder
+		want    *Query
+		wantErr error
+	}{
+		{
+			name: "union all",
+			s: NewSelector[Order](db).Select(C("Id")).Where(C("Id").GT(10)).
+				UnionAll(NewSelector[OrderArchive](db).Select(C("Id")).Where(C("Id").GT(20))),
+			want: &Query{
+				SQL:  "SELECT `id` FROM `order` WHERE `id` > ? UNION ALL SELECT `id` FROM `order_archive` WHERE `id` > ?;",
+				Args: []any{10, 20},
+			},
+		},
+		{
+			name: "union order by limit",
+			s: NewSelector[Order](db).Select(C("Id"), C("UsingCol1").As("col")).
+				Union(NewSelector[OrderArchive](db).Select(C("Id"), C("UsingCol2"))).
+				OrderBy(Desc("Id"), Asc("col")).Limit(10).Offset(5),
+			want: &Query{
+				SQL: "SELECT `id`,`using_col1` as `col` FROM `order` UNION SELECT `id`,`using_col2` FROM `order_archive`" +
+					" ORDER BY `id` DESC,`col` ASC LIMIT ? OFFSET ?;",
+				Args: []any{int32(10), int32(5)},
+			},
+		},
+		{
+			name: "limited part",
+			s: NewSelector[Order](db).Where(C("Id").EQ(1)).
+				UnionAll(NewSelector[OrderArchive](db).Limit(3)),
+			want: &Query{
+				SQL:  "SELECT * FROM `order` WHERE `id` = ? UNION ALL (SELECT * FROM `order_archive` LIMIT ?);",
+				Args: []any{1, int32(3)},
+			},
+		},
+		{
+			name: "nested",
+			s: NewSelector[Order](db).Select(C("Id")).Where(C("Id").EQ(1)).
+				Union(NewSelector[OrderArchive](db).Select(C("Id")).Where(C("Id").EQ(2)).
+					UnionAll(NewSelector[Item](db).Select(C("Id")).Where(C("Id").EQ(3)))),
+			want: &Query{
+				SQL: "SELECT `id` FROM `order` WHERE `id` = ? UNION (SELECT `id` FROM `order_archive` WHERE `id` = ?" +
+					" UNION ALL SELECT `id` FROM `item` WHERE `id` = ?);",
+				Args: []any{1, 2, 3},
+			},
+		},
+		{
+			name: "as subquery",
+			s: func() QueryBuilder {
+				sub := NewSelector[Order](db).Select(C("Id")).
+					UnionAll(NewSelector[OrderArchive](db).Select(C("Id"))).AsSubquery("sub")
+				return NewSelector[Order](db).Select(Count("*")).FromTable(sub).Where(sub.C("Id").GT(5))
+			}(),
+			want: &Query{
+				SQL: "SELECT COUNT(*) FROM (SELECT `id` FROM `order` UNION ALL SELECT `id` FROM `order_archive`) AS `sub`" +
+					" WHERE `sub`.`id` > ?;",
+				Args: []any{5},
+			},
+		},
+		{
+			name: "unknown order by",
+			s: NewSelector[Order](db).Select(C("Id")).
+				Union(NewSelector[OrderArchive](db).Select(C("Id"))).OrderBy(Asc("UsingCol1")),
+			wantErr: err2.NewErrUnknownColumn("UsingCol1"),
+		},
+		{
+			name: "intersect unsupported",
+			s: NewSelector[Order](db).Select(C("Id")).
+				Intersect(NewSelector[OrderArchive](db).Select(C("Id"))),
+			wantErr: err2.NewErrUnsupportedClause("INTERSECT"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.Build()
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSetQuery_Postgres(t *testing.T) {
+	db := memoryDB(DBWithDialect(Postgres))
+	q, err := NewSelector[Order](db).Select(C("Id")).Where(C("Id").GT(1)).
+		Intersect(NewSelector[OrderArchive](db).Select(C("Id")).Where(C("Id").LT(9))).
+		Except(NewSelector[Item](db).Select(C("Id")).Where(C("Id").EQ(5))).
+		Limit(10).Build()
+	require.NoError(t, err)
+	assert.Equal(t, &Query{
+		SQL: `SELECT "id" FROM "order" WHERE "id" > $1 INTERSECT SELECT "id" FROM "order_archive" WHERE "id" < $2` +
+			` EXCEPT SELECT "id" FROM "item" WHERE "id" = $3 LIMIT $4;`,
+		Args: []any{1, 9, 5, int32(10)},
+	}, q)
+}
+
+func TestSetQuery_GetMulti(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+
+	var typ string
+	db, err := OpenDB(mockDB, DBWithMiddleware(func(next Handler) Handler {
+		return func(ctx context.Context, qc *QueryContext) *QueryResult {
+			typ = qc.Type
+			return next(ctx, qc)
+		}
+	}))
+	require.NoError(t, err)
+
+	mock.ExpectQuery("SELECT `id` FROM `order` WHERE `id` > \\? UNION ALL SELECT `id` FROM `order_archive` WHERE `id` > \\?").
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3).AddRow(4))
+
+	got, err := NewSelector[Order](db).Select(C("Id")).Where(C("Id").GT(1)).
+		UnionAll(NewSelector[OrderArchive](db).Select(C("Id")).Where(C("Id").GT(2))).
+		GetMulti(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []*Order{{Id: 3}, {Id: 4}}, got)
+	assert.Equal(t, "SELECT", typ)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetQuery_sqlite(t *testing.T) {
+	db := sqliteDB(t)
+	ctx := context.Background()
+	res := NewInserter[TestModel](db).Values(
+		&TestModel{Id: 1, FirstName: "liu", Age: 18},
+		&TestModel{Id: 2, FirstName: "wang", Age: 30},
+		&TestModel{Id: 3, FirstName: "zhang", Age: 40},
+	).Exec(ctx)
+	require.NoError(t, res.(Result).Err())
+
+	got, err := NewSelector[TestModel](db).Where(C("Age").LT(20)).
+		UnionAll(NewSelector[TestModel](db).Where(C("Age").GT(35))).
+		Union(NewSelector[TestModel](db).Where(C("Id").EQ(1))).
+		OrderBy(Desc("Id")).GetMulti(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []*TestModel{
+		{Id: 3, FirstName: "zhang", Age: 40},
+		{Id: 1, FirstName: "liu", Age: 18},
+	}, got)
+
+	got, err = NewSelector[TestModel](db).
+		Except(NewSelector[TestModel](db).Where(C("Id").EQ(2))).
+		OrderBy(Asc("Id")).GetMulti(ctx)
+	require.NoError(t, err)
+	assert.Len(t, got, 2)
+	assert.Equal(t, int64(3), got[1].Id)
+
+	// 带 LIMIT 的查询和嵌套的集合操作需要改写成子查询
+	q := NewSelector[TestModel](db).Where(C("Id").EQ(1)).
+		UnionAll(NewSelector[TestModel](db).OrderBy(Desc("Age")).Limit(1)).
+		Union(NewSelector[TestModel](db).Where(C("Id").EQ(2)).
+			UnionAll(NewSelector[TestModel](db).Where(C("Id").EQ(3))))
+	sql, err := q.Build()
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "test_model" WHERE "id" = ? UNION ALL SELECT * FROM (SELECT * FROM "test_model" ORDER BY "age" DESC LIMIT ?)`+
+		` UNION SELECT * FROM (SELECT * FROM "test_model" WHERE "id" = ? UNION ALL SELECT * FROM "test_model" WHERE "id" = ?);`, sql.SQL)
+	got, err = q.OrderBy(Asc("Id")).GetMulti(ctx)
+	require.NoError(t, err)
+	assert.Len(t, got, 3)
+	assert.Equal(t, int64(1), got[0].Id)
+	assert.Equal(t, int64(3), got[2].Id)
+}
@@ -0,0 +1,137 @@
+package go_orm
+
+import (
+	"context"
+	"database/sql"
+	err2 "go-orm/internal/err"
+	"reflect"
+)
+
+type Updater[T any] struct {
+	builder
+	sess    Session
+	val     *T
+	assigns []Assignable
+	where   []Predicate
+}
+
+func NewUpdater[T any](sess Session) *Updater[T] {
+	c := sess.getCore()
+	return &Updater[T]{
+		builder: builder{
+			core: c,
+		},
+		sess: sess,
+	}
+}
+
+// Update 指定实体，Set 中使用 C("xxx") 的列会从实体里面取值
+// 没有调用 Set 的时候，会更新实体除了主键以外的所有列
+func (u *Updater[T]) Update(t *T) *Updater[T] {
+	u.val = t
+	return u
+}
+
+func (u *Updater[T]) Set(assigns ...Assignable) *Updater[T] {
+	u.assigns = assigns
+	return u
+}
+
+func (u *Updater[T]) Where(ps ...Predicate) *Updater[T] {
+	u.where = ps
+	return u
+}
+
+func (u *Updater[T]) Build() (*Query, error) {
+	if len(u.assigns) == 0 && u.val == nil {
+		return nil, err2.ErrNoUpdatedColumns
+	}
+
+	var (
+		t   = u.val
+		err error
+	)
+	if t == nil {
+		t = new(T)
+	}
+	u.m, err = u.r.Get(t)
+	if err != nil {
+		return nil, err
+	}
+
+	u.reset()
+	u.sb.WriteString("UPDATE ")
+	u.quote(u.m.TableName)
+	u.sb.WriteString(" SET ")
+
+	val := reflect.ValueOf(t).Elem()
+	if len(u.assigns) == 0 {
+		// 主键不会出现在 SET 里面
+		cnt := 0
+		for _, fd := range u.m.Columns {
+			if fd.PrimaryKey {
+				continue
+			}
+			if cnt > 0 {
+				u.sb.WriteByte(',')
+			}
+			u.quote(fd.ColName)
+			u.sb.WriteByte('=')
+			u.parameter(val.FieldByIndex(fd.Index).Interface())
+			cnt++
+		}
+		if cnt == 0 {
+			return nil, err2.ErrNoUpdatedColumns
+		}
+	}
+
+	for i, assign := range u.assigns {
+		if i > 0 {
+			u.sb.WriteByte(',')
+		}
+		switch a := assign.(type) {
+		case Column:
+			// 列的值来自实体，没有实体的时候读到的都是零值
+			if u.val == nil {
+				return nil, err2.ErrColumnWithoutEntity
+			}
+			fd, ok := u.m.FieldMap[a.name]
+			if !ok {
+				return nil, err2.NewErrUnknownColumn(a.name)
+			}
+			u.quote(fd.ColName)
+			u.sb.WriteByte('=')
+			u.parameter(val.FieldByIndex(fd.Index).Interface())
+		case Assignment:
+			if err = u.buildColumn(a.column); err != nil {
+				return nil, err
+			}
+			u.sb.WriteByte('=')
+			if err = u.buildAssignValue(a.val); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, err2.NewErrUnsupportedAssignableType(assign)
+		}
+	}
+
+	if len(u.where) > 0 {
+		u.sb.WriteString(" WHERE ")
+		if err = u.buildPredicates(u.where); err != nil {
+			return nil, err
+		}
+	}
+
+	u.sb.WriteByte(';')
+	return &Query{
+		SQL:  u.sb.String(),
+		Args: u.args,
+	}, nil
+}
+
+func (u *Updater[T]) Exec(ctx context.Context) sql.Result {
+	return exec(ctx, u.sess, u.core, &QueryContext{
+		Type:    "UPDATE",
+		Builder: u,
+	})
+}
@@ -0,0 +1,126 @@
+package go_orm
+
+import (
+	"database/sql"
+	"github.com/stretchr/testify/assert"
+	err2 "go-orm/internal/err"
+	"testing"
+)
+
+type UpdateModel struct {
+	Uid  int64 `orm:"primary_key"`
+	Id   int64
+	Name string
+}
+
+func TestUpdater_Build(t *testing.T) {
+	db := memoryDB()
+	tests := []struct {
+		name    string
+		u       QueryBuilder
+		want    *Query
+		wantErr error
+	}{
+		{
+			name:    "no columns",
+			u:       NewUpdater[TestModel](db),
+			wantErr: ErrNoUpdatedColumns,
+		},
+		{
+			name: "all columns",
+			u: NewUpdater[TestModel](db).Update(&TestModel{
+				Id:        12,
+				FirstName: "liu",
+				Age:       28,
+				LastName:  &sql.NullString{Valid: true, String: "quan"},
+			}),
+			want: &Query{
+				SQL:  "UPDATE `test_model` SET `first_name`=?,`age`=?,`last_name`=?;",
+				Args: []any{"liu", int8(28), &sql.NullString{Valid: true, String: "quan"}},
+			},
+		},
+		{
+			name: "primary key tag",
+			u: NewUpdater[UpdateModel](db).Update(&UpdateModel{
+				Uid:  1,
+				Id:   2,
+				Name: "liu",
+			}).Where(C("Uid").EQ(1)),
+			want: &Query{
+				SQL:  "UPDATE `update_model` SET `id`=?,`name`=? WHERE `uid` = ?;",
+				Args: []any{int64(2), "liu", 1},
+			},
+		},
+		{
+			name:    "only primary key",
+			u:       NewUpdater[Item](db).Update(&Item{Id: 1}),
+			wantErr: err2.ErrNoUpdatedColumns,
+		},
+		{
+			name: "set columns from entity",
+			u: NewUpdater[TestModel](db).Update(&TestModel{
+				Id:        12,
+				FirstName: "liu",
+				Age:       28,
+			}).Set(C("FirstName"), C("Age")).Where(C("Id").EQ(12)),
+			want: &Query{
+				SQL:  "UPDATE `test_model` SET `first_name`=?,`age`=? WHERE `id` = ?;",
+				Args: []any{"liu", int8(28), 12},
+			},
+		},
+		{
+			name: "assign",
+			u: NewUpdater[TestModel](db).Update(&TestModel{
+				FirstName: "liu",
+			}).Set(C("FirstName"), Assign("Age", 30)).Where(C("Id").EQ(12), C("Age").LT(18)),
+			want: &Query{
+				SQL:  "UPDATE `test_model` SET `first_name`=?,`age`=? WHERE (`id` = ?) AND (`age` < ?);",
+				Args: []any{"liu", 30, 12, 18},
+			},
+		},
+		{
+			name: "assign without entity",
+			u:    NewUpdater[TestModel](db).Set(Assign("Age", 30)),
+			want: &Query{
+				SQL:  "UPDATE `test_model` SET `age`=?;",
+				Args: []any{30},
+			},
+		},
+		{
+			name: "assign raw",
+			u:    NewUpdater[TestModel](db).Set(Assign("Age", Raw("`age` + ?", 1)), Assign("FirstName", "liu")).Where(C("Id").EQ(12)),
+			want: &Query{
+				SQL:  "UPDATE `test_model` SET `age`=`age` + ?,`first_name`=? WHERE `id` = ?;",
+				Args: []any{1, "liu", 12},
+			},
+		},
+		{
+			name: "assign math",
+			u:    NewUpdater[TestModel](db).Set(Assign("Age", C("Age").Add(1)), Assign("FirstName", Fn("UPPER", C("FirstName")))),
+			want: &Query{
+				SQL:  "UPDATE `test_model` SET `age`=`age` + ?,`first_name`=UPPER(`first_name`);",
+				Args: []any{1},
+			},
+		},
+		{
+			name:    "column without entity",
+			u:       NewUpdater[TestModel](db).Set(C("Age")),
+			wantErr: ErrColumnWithoutEntity,
+		},
+		{
+			name:    "unknown column",
+			u:       NewUpdater[TestModel](db).Set(Assign("Invalid", 30)),
+			wantErr: err2.NewErrUnknownColumn("Invalid"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.u.Build()
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package go_orm
+
+// WindowSpec OVER 里面的部分，PartitionBy 或者 OrderBy
+type WindowSpec interface {
+	windowSpec()
+}
+
+// Partition 对应 PARTITION BY
+type Partition struct {
+	cols []Column
+}
+
+func (p Partition) windowSpec() {}
+
+func (o OrderBy) windowSpec() {}
+
+func PartitionBy(cols ...Column) Partition {
+	return Partition{
+		cols: cols,
+	}
+}
+
+// Window 窗口函数，例如
+// RowNumber().Over(PartitionBy(C("UserId")), OrderBy(Desc("CreatedAt"))).As("rn")
+type Window struct {
+	// fn 窗口函数本身，FuncExpr 或者 Aggregate
+	fn          Expression
+	partitionBy []Column
+	orderBy     []OrderBy
+	alias       string
+}
+
+// Window 只能出现在 SELECT 列表里面，不能作为 WHERE 或者 HAVING 的条件
+func (w Window) selectable() {}
+
+func (w Window) As(alias string) Window {
+	w.alias = alias
+	return w
+}
+
+func newWindow(fn Expression, specs []WindowSpec) Window {
+	w := Window{
+		fn: fn,
+	}
+	for _, spec := range specs {
+		switch s := spec.(type) {
+		case Partition:
+			w.partitionBy = append(w.partitionBy, s.cols...)
+		case OrderBy:
+			w.orderBy = append(w.orderBy, s)
+		}
+	}
+	return w
+}
+
+// Over 作为窗口函数使用，例如 Sum("Amount").Over(PartitionBy(C("UserId")))
+func (a Aggregate) Over(specs ...WindowSpec) Window {
+	return newWindow(a, specs)
+}
+
+// Over 作为窗口函数使用
+func (f FuncExpr) Over(specs ...WindowSpec) Window {
+	return newWindow(f, specs)
+}
+
+func RowNumber() FuncExpr {
+	return Fn("ROW_NUMBER")
+}
+
+func Rank() FuncExpr {
+	return Fn("RANK")
+}
+
+func DenseRank() FuncExpr {
+	return Fn("DENSE_RANK")
+}
+
+// Lag 前面第 offset 行的值，args 依次是 offset 和默认值，都可以省略
+func Lag(col string, args ...any) FuncExpr {
+	return Fn("LAG", append([]any{C(col)}, args...)...)
+}
+
+// Lead 后面第 offset 行的值，args 依次是 offset 和默认值，都可以省略
+func Lead(col string, args ...any) FuncExpr {
+	return Fn("LEAD", append([]any{C(col)}, args...)...)
+}
@@ -0,0 +1,131 @@
+package go_orm
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	err2 "go-orm/internal/err"
+	"testing"
+)
+
+type Trade struct {
+	Id        int64
+	UserId    int64
+	Amount    int64
+	CreatedAt int64
+}
+
+func TestSelector_Window(t *testing.T) {
+	db := memoryDB()
+	tests := []struct {
+		name    string
+		s       QueryBuilder
+		want    *Query
+		wantErr error
+	}{
+		{
+			name: "row number",
+			s: NewSelector[Trade](db).Select(C("Id"),
+				RowNumber().Over(PartitionBy(C("UserId")), OrderBy(Desc("CreatedAt"))).As("rn")),
+			want: &Query{
+				SQL: "SELECT `id`,ROW_NUMBER() OVER (PARTITION BY `user_id` ORDER BY `created_at` DESC) as `rn` FROM `trade`;",
+			},
+		},
+		{
+			name: "rank",
+			s: NewSelector[Trade](db).Select(Rank().Over(Desc("Amount")).As("r"),
+				DenseRank().Over(PartitionBy(C("UserId"), C("CreatedAt")), Desc("Amount"), Asc("Id")).As("dr")),
+			want: &Query{
+				SQL: "SELECT RANK() OVER (ORDER BY `amount` DESC) as `r`," +
+					"DENSE_RANK() OVER (PARTITION BY `user_id`,`created_at` ORDER BY `amount` DESC,`id` ASC) as `dr` FROM `trade`;",
+			},
+		},
+		{
+			name: "lag lead",
+			s: NewSelector[Trade](db).Select(
+				Lag("Amount").Over(Asc("CreatedAt")).As("prev"),
+				Lead("Amount", 2, 0).Over(PartitionBy(C("UserId")), Asc("CreatedAt")).As("next")).
+				Where(C("UserId").EQ(7)),
+			want: &Query{
+				SQL: "SELECT LAG(`amount`) OVER (ORDER BY `created_at` ASC) as `prev`," +
+					"LEAD(`amount`,?,?) OVER (PARTITION BY `user_id` ORDER BY `created_at` ASC) as `next` FROM `trade` WHERE `user_id` = ?;",
+				Args: []any{2, 0, 7},
+			},
+		},
+		{
+			name: "aggregate",
+			s: NewSelector[Trade](db).Select(C("Id"), Sum("Amount").Over(PartitionBy(C("UserId"))).As("total"),
+				Count("*").Over().As("cnt")),
+			want: &Query{
+				SQL: "SELECT `id`,SUM(`amount`) OVER (PARTITION BY `user_id`) as `total`,COUNT(*) OVER () as `cnt` FROM `trade`;",
+			},
+		},
+		{
+			name: "latest per group",
+			s: func() QueryBuilder {
+				sub := NewSelector[Trade](db).Select(C("Id"), C("UserId"),
+					RowNumber().Over(PartitionBy(C("UserId")), Desc("CreatedAt")).As("rn")).AsSubquery("t")
+				return NewSelector[Trade](db).Select(sub.C("Id"), sub.C("UserId")).FromTable(sub).Where(sub.C("rn").EQ(1))
+			}(),
+			want: &Query{
+				SQL: "SELECT `t`.`id`,`t`.`user_id` FROM (SELECT `id`,`user_id`,ROW_NUMBER() OVER (PARTITION BY `user_id`" +
+					" ORDER BY `created_at` DESC) as `rn` FROM `trade`) AS `t` WHERE `t`.`rn` = ?;",
+				Args: []any{1},
+			},
+		},
+		{
+			name:    "window in where",
+			s:       NewSelector[Trade](db).Where(C("Amount").GT(Sum("Amount").Over(PartitionBy(C("UserId"))))),
+			wantErr: err2.NewErrUnsupportedExpressionType(Sum("Amount").Over(PartitionBy(C("UserId")))),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.Build()
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSelector_Window_sqlite(t *testing.T) {
+	db := sqliteDB(t)
+	ctx := context.Background()
+	_, err := db.db.Exec("CREATE TABLE `trade`(" +
+		"`id` INTEGER PRIMARY KEY," +
+		"`user_id` INTEGER NOT NULL," +
+		"`amount` INTEGER NOT NULL," +
+		"`created_at` INTEGER NOT NULL)")
+	require.NoError(t, err)
+
+	res := NewInserter[Trade](db).Values(
+		&Trade{Id: 1, UserId: 1, Amount: 10, CreatedAt: 100},
+		&Trade{Id: 2, UserId: 1, Amount: 20, CreatedAt: 200},
+		&Trade{Id: 3, UserId: 2, Amount: 30, CreatedAt: 150},
+		&Trade{Id: 4, UserId: 2, Amount: 40, CreatedAt: 120},
+	).Exec(ctx)
+	require.NoError(t, res.(Result).Err())
+
+	type Ranked struct {
+		Id     int64
+		UserId int64
+		Rn     int64
+		Total  int64
+		Prev   int64
+	}
+	sub := NewSelector[Trade](db).Select(C("Id"), C("UserId"),
+		RowNumber().Over(PartitionBy(C("UserId")), Desc("CreatedAt")).As("rn"),
+		Sum("Amount").Over(PartitionBy(C("UserId"))).As("total"),
+		Lag("Amount", 1, 0).Over(PartitionBy(C("UserId")), Asc("CreatedAt")).As("prev")).AsSubquery("t")
+	got, err := Project[Ranked](ctx, NewSelector[Trade](db).
+		Select(sub.C("Id"), sub.C("UserId"), sub.C("rn"), sub.C("total"), sub.C("prev")).
+		FromTable(sub).Where(sub.C("rn").EQ(1)).OrderBy(sub.C("UserId").Asc()))
+	require.NoError(t, err)
+	assert.Equal(t, []*Ranked{
+		{Id: 2, UserId: 1, Rn: 1, Total: 30, Prev: 10},
+		{Id: 3, UserId: 2, Rn: 1, Total: 70, Prev: 40},
+	}, got)
+}